@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
 	"fmt"
 	"reflect"
 	"strings"
@@ -19,7 +21,12 @@ func schedule(ctx context.Context, c eurodnsgo.Client, sr *eurodnsgo.SoapRequest
 	// Block until result returns
 	_ = <-ch
 
-	return nil
+	return sr.Err()
+}
+
+// zoneAttr returns the span attribute holding the name of a zone
+func zoneAttr(name string) eurodnsgo.Attr {
+	return eurodnsgo.Attr{Key: eurodnsgo.AttrZone, Value: name}
 }
 
 func parseTag(t reflect.StructTag) (string, string) {
@@ -65,7 +72,11 @@ func xmlEncode(val interface{}) ([]byte, error) {
 		case reflect.Int:
 			res += fmt.Sprintf("<%s>%d</%s>", tag, v.Field(i).Int(), tag)
 		case reflect.String:
-			res += fmt.Sprintf("<%s>%s</%s>", tag, v.Field(i).String(), tag)
+			var buf bytes.Buffer
+			if err := xml.EscapeText(&buf, []byte(v.Field(i).String())); err != nil {
+				return nil, err
+			}
+			res += fmt.Sprintf("<%s>%s</%s>", tag, buf.String(), tag)
 		case reflect.Struct:
 			sub, err := xmlEncode(v)
 			if err != nil {
@@ -87,6 +98,8 @@ var (
 	Change MutationType = "change"
 	// Remove to remove an entity
 	Remove MutationType = "remove"
+	// Unchanged reports that an entity already matched the desired state
+	Unchanged MutationType = "unchanged"
 )
 
 // GetDomainList returns an string list with all manageble domain names
@@ -135,25 +148,63 @@ func GetZoneInfo(ctx context.Context, c eurodnsgo.Client, domain string) (Zone,
 	return v.Zone, err
 }
 
-func addRecordRequest(v interface{}, z Zone, r Record) (*eurodnsgo.SoapRequest, error) {
+// zoneUpdateRequest builds a single zone:update request which performs
+// all given additions, changes and removals at once
+func zoneUpdateRequest(v interface{}, z Zone, add, change, remove []Record) (*eurodnsgo.SoapRequest, error) {
 	sr := eurodnsgo.NewSoapRequest("zone", "update", &v)
 
-	rr, err := xmlEncode(r)
+	records := &eurodnsgo.SoapParamContainer{}
+	if len(add) > 0 {
+		zoneAdd := &eurodnsgo.SoapParamContainer{}
+		for _, r := range add {
+			rr, err := xmlEncode(r)
+			if err != nil {
+				return sr, err
+			}
+			zoneAdd.AddParam(eurodnsgo.NewParam("zone", "record", rr))
+		}
+		records.AddParam(eurodnsgo.NewParam("zone", "add", zoneAdd))
+	}
+	if len(change) > 0 {
+		zoneChange := &eurodnsgo.SoapParamContainer{}
+		for _, r := range change {
+			rr, err := xmlEncode(r)
+			if err != nil {
+				return sr, err
+			}
+			zoneChange.AddParam(eurodnsgo.NewParam("zone", "record", rr, eurodnsgo.Attr{Key: "id", Value: r.ID}))
+		}
+		records.AddParam(eurodnsgo.NewParam("zone", "change", zoneChange))
+	}
+	if len(remove) > 0 {
+		zoneRemove := &eurodnsgo.SoapParamContainer{}
+		for _, r := range remove {
+			zoneRemove.AddParam(eurodnsgo.NewParam("zone", "record", nil, eurodnsgo.Attr{Key: "id", Value: r.ID}))
+		}
+		records.AddParam(eurodnsgo.NewParam("zone", "remove", zoneRemove))
+	}
 
-	zoneRecord := eurodnsgo.NewParam("zone", "record", rr)
-	zoneAdd := eurodnsgo.NewParam("zone", "add", zoneRecord)
-	zoneRecords := eurodnsgo.NewParam("zone", "records", zoneAdd)
-	zoneName := eurodnsgo.NewParam("zone", "name", z.Name)
+	sr.AddParam(eurodnsgo.NewParam("zone", "name", z.Name))
+	sr.AddParam(eurodnsgo.NewParam("zone", "records", records))
+	return sr, nil
+}
 
-	sr.AddParam(zoneName)
-	sr.AddParam(zoneRecords)
-	return sr, err
+func addRecordRequest(v interface{}, z Zone, r Record) (*eurodnsgo.SoapRequest, error) {
+	return zoneUpdateRequest(v, z, []Record{r}, nil, nil)
 }
 
-// ZoneRecordAdd adds a new Record object to a Zone
+// ZoneRecordAdd adds a new Record object to a Zone. The record is validated
+// against the known records of the zone first, unless the context was
+// created by SkipValidation.
 func ZoneRecordAdd(ctx context.Context, c eurodnsgo.Client, z Zone, r Record) error {
 	var v interface{}
 
+	if !ValidationSkipped(ctx) {
+		if err := z.ValidateRecord(r); err != nil {
+			return err
+		}
+	}
+
 	rr, err := addRecordRequest(v, z, r)
 	if err != nil {
 		return err
@@ -164,24 +215,21 @@ func ZoneRecordAdd(ctx context.Context, c eurodnsgo.Client, z Zone, r Record) er
 }
 
 func changeRecordRequest(v interface{}, z Zone, r Record) (*eurodnsgo.SoapRequest, error) {
-	sr := eurodnsgo.NewSoapRequest("zone", "update", &v)
-
-	rr, err := xmlEncode(r)
-
-	zoneRecord := eurodnsgo.NewParam("zone", "record", rr, eurodnsgo.Attr{Key: "id", Value: r.ID})
-	zoneChange := eurodnsgo.NewParam("zone", "change", zoneRecord)
-	zoneRecords := eurodnsgo.NewParam("zone", "records", zoneChange)
-	zoneName := eurodnsgo.NewParam("zone", "name", z.Name)
-
-	sr.AddParam(zoneName)
-	sr.AddParam(zoneRecords)
-	return sr, err
+	return zoneUpdateRequest(v, z, nil, []Record{r}, nil)
 }
 
-// ZoneRecordChange changes a Record object inside a Zone
+// ZoneRecordChange changes a Record object inside a Zone. The record is
+// validated against the known records of the zone first, unless the context
+// was created by SkipValidation.
 func ZoneRecordChange(ctx context.Context, c eurodnsgo.Client, z Zone, r Record) error {
 	var v interface{}
 
+	if !ValidationSkipped(ctx) {
+		if err := z.ValidateRecord(r); err != nil {
+			return err
+		}
+	}
+
 	cr, err := changeRecordRequest(v, z, r)
 	if err != nil {
 		return err
@@ -192,15 +240,8 @@ func ZoneRecordChange(ctx context.Context, c eurodnsgo.Client, z Zone, r Record)
 }
 
 func deleteRecordRequest(v interface{}, z Zone, r Record) *eurodnsgo.SoapRequest {
-	sr := eurodnsgo.NewSoapRequest("zone", "update", &v)
-
-	zoneRecord := eurodnsgo.NewParam("zone", "record", nil, eurodnsgo.Attr{Key: "id", Value: r.ID})
-	zoneRemove := eurodnsgo.NewParam("zone", "remove", zoneRecord)
-	zoneRecords := eurodnsgo.NewParam("zone", "records", zoneRemove)
-	zoneName := eurodnsgo.NewParam("zone", "name", z.Name)
-
-	sr.AddParam(zoneName)
-	sr.AddParam(zoneRecords)
+	// removals never encode record contents, so no error can occur
+	sr, _ := zoneUpdateRequest(v, z, nil, nil, []Record{r})
 	return sr
 }
 
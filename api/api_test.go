@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"net"
 	"strings"
 	"testing"
 	"unicode"
@@ -23,6 +25,23 @@ func testParams(t *testing.T, sr *eurodnsgo.SoapRequest, expected string) {
 	}
 }
 
+// testClient records all requests instead of sending them
+type testClient struct {
+	requests []*eurodnsgo.SoapRequest
+}
+
+func (c *testClient) Schedule(ctx context.Context, sr *eurodnsgo.SoapRequest) (chan []byte, error) {
+	c.requests = append(c.requests, sr)
+	ch := make(chan []byte, 1)
+	ch <- []byte{}
+	return ch, nil
+}
+
+func (c *testClient) Call(ctx context.Context, sr *eurodnsgo.SoapRequest) error {
+	c.requests = append(c.requests, sr)
+	return nil
+}
+
 func TestDomainListParams(t *testing.T) {
 	e := `<?xml version="1.0" encoding="UTF-8"?>
 <request xmlns:domain="http://www.eurodns.com/domain">
@@ -92,3 +111,42 @@ func TestDeleteRecordRequest(t *testing.T) {
 
 	testParams(t, sr, e)
 }
+
+// unreachableClient returns a client for a host which refuses connections
+func unreachableClient(t *testing.T) eurodnsgo.Client {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	c, err := eurodnsgo.NewClient(eurodnsgo.ClientConfig{Host: l.Addr().String(), Username: "username", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestScheduledErrors(t *testing.T) {
+	c := unreachableClient(t)
+	ctx := context.TODO()
+
+	if _, err := GetZoneInfo(ctx, c, "example.org"); err == nil {
+		t.Error("expected GetZoneInfo to return the error of the request")
+	}
+	r := Record{Host: "www", Type: RecordTypeA, Data: "192.0.2.1"}
+	if err := ZoneRecordAdd(ctx, c, Zone{Name: "example.org"}, r); err == nil {
+		t.Error("expected ZoneRecordAdd to return the error of the request")
+	}
+}
+
+func TestXMLEncodeEscapesData(t *testing.T) {
+	b, err := xmlEncode(Record{Type: RecordTypeURL, Data: "https://example.org/?a=1&b=<2>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), "<record:data>https://example.org/?a=1&amp;b=&lt;2&gt;</record:data>") {
+		t.Errorf("expected record data to be escaped, got %s", b)
+	}
+}
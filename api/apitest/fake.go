@@ -0,0 +1,420 @@
+// Package apitest provides an in-memory implementation of the services of
+// the api package, so code depending on api.DomainService, api.ZoneService
+// or api.RecordService can be unit tested without a client or XML.
+//
+//	f := apitest.New()
+//	f.AddZone(api.Zone{Name: "example.org"})
+//
+//	svc := f.Services()
+//	err := svc.Records.Add(ctx, zone, record)
+//
+// The fake only stores zones. Changes are planned and validated by the
+// functions of the api package, like PlanUpsert, PlanRRSet and PlanSOA, so
+// they behave like the real services. Unknown zones and records are
+// reported with the same eurodnsgo.Error as the API.
+package apitest
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// codeNotFound is the result code of the API for unknown objects
+const codeNotFound = 2303
+
+func errNotFound() error {
+	return &eurodnsgo.Error{Code: codeNotFound, Message: "Object does not exist"}
+}
+
+// Fake keeps domains, zones and records in memory
+type Fake struct {
+	mu      sync.Mutex
+	domains map[string]bool
+	zones   map[string]*api.Zone
+	nextID  int
+	errors  map[string]error
+	calls   []string
+}
+
+// New returns a Fake without any domains or zones
+func New() *Fake {
+	return &Fake{
+		domains: make(map[string]bool),
+		zones:   make(map[string]*api.Zone),
+		errors:  make(map[string]error),
+		nextID:  1,
+	}
+}
+
+// Services returns the services backed by the fake
+func (f *Fake) Services() api.Services {
+	return api.Services{
+		Domains: f.Domains(),
+		Zones:   f.Zones(),
+		Records: f.Records(),
+	}
+}
+
+// Domains returns the DomainService backed by the fake
+func (f *Fake) Domains() api.DomainService {
+	return domains{f}
+}
+
+// Zones returns the ZoneService backed by the fake
+func (f *Fake) Zones() api.ZoneService {
+	return zones{f}
+}
+
+// Records returns the RecordService backed by the fake
+func (f *Fake) Records() api.RecordService {
+	return records{f}
+}
+
+// AddDomain registers domains
+func (f *Fake) AddDomain(names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, name := range names {
+		f.domains[name] = true
+	}
+}
+
+// AddZone stores a zone, replacing any zone with the same name. Records
+// without an ID are assigned one, the zone is registered as domain too.
+func (f *Fake) AddZone(z api.Zone) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored := &api.Zone{Name: z.Name}
+	for _, r := range z.Records {
+		rr := *r
+		if rr.ID == 0 {
+			rr.ID = f.nextID
+		}
+		if rr.ID >= f.nextID {
+			f.nextID = rr.ID + 1
+		}
+		stored.Records = append(stored.Records, &rr)
+	}
+	f.zones[z.Name] = stored
+	f.domains[z.Name] = true
+}
+
+// Zone returns a copy of the current state of a zone
+func (f *Fake) Zone(name string) (api.Zone, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	z, ok := f.zones[name]
+	if !ok {
+		return api.Zone{}, false
+	}
+	return copyZone(z), true
+}
+
+// Fail makes all following calls of an operation return the error, until
+// it is cleared by passing a nil error. Operations are named after the
+// service and method, like "Zones.Info" or "Records.Add".
+func (f *Fake) Fail(op string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.errors, op)
+		return
+	}
+	f.errors[op] = err
+}
+
+// Calls returns the operations called so far, in order
+func (f *Fake) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// call records the operation and returns its injected error, it must be
+// called with the mutex held
+func (f *Fake) call(op string) error {
+	f.calls = append(f.calls, op)
+	return f.errors[op]
+}
+
+func copyZone(z *api.Zone) api.Zone {
+	c := api.Zone{Name: z.Name}
+	for _, r := range z.Records {
+		rr := *r
+		c.Records = append(c.Records, &rr)
+	}
+	return c
+}
+
+// apply performs the actions of a plan on the stored zone, which is left
+// untouched when any action fails. Like the API, it does not validate the
+// resulting zone. It must be called with the mutex held.
+func (f *Fake) apply(p api.Plan) error {
+	z, ok := f.zones[p.Zone]
+	if !ok {
+		return errNotFound()
+	}
+
+	updated := copyZone(z)
+	find := func(id int) int {
+		for i, r := range updated.Records {
+			if r.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	nextID := f.nextID
+	for _, a := range p.Actions {
+		r := a.Record
+		switch a.Type {
+		case api.Add:
+			r.ID = nextID
+			nextID++
+			updated.Records = append(updated.Records, &r)
+		case api.Change:
+			i := find(r.ID)
+			if i == -1 {
+				return errNotFound()
+			}
+			updated.Records[i] = &r
+		case api.Remove:
+			i := find(r.ID)
+			if i == -1 {
+				return errNotFound()
+			}
+			updated.Records = append(updated.Records[:i], updated.Records[i+1:]...)
+		}
+	}
+
+	f.nextID = nextID
+	f.zones[p.Zone] = &updated
+	return nil
+}
+
+type domains struct {
+	f *Fake
+}
+
+func (s domains) List(ctx context.Context) ([]string, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Domains.List"); err != nil {
+		return nil, err
+	}
+	return sortedKeys(s.f.domains), nil
+}
+
+type zones struct {
+	f *Fake
+}
+
+func (s zones) List(ctx context.Context) ([]string, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.List"); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(s.f.zones))
+	for name := range s.f.zones {
+		names[name] = true
+	}
+	return sortedKeys(names), nil
+}
+
+func (s zones) Info(ctx context.Context, name string) (api.Zone, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.Info"); err != nil {
+		return api.Zone{}, err
+	}
+	z, ok := s.f.zones[name]
+	if !ok {
+		return api.Zone{}, errNotFound()
+	}
+	return copyZone(z), nil
+}
+
+func (s zones) Plan(ctx context.Context, desired api.Zone) (api.Plan, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.Plan"); err != nil {
+		return api.Plan{}, err
+	}
+	if !api.ValidationSkipped(ctx) {
+		if err := desired.Validate(); err != nil {
+			return api.Plan{}, err
+		}
+	}
+	z, ok := s.f.zones[desired.Name]
+	if !ok {
+		return api.Plan{}, errNotFound()
+	}
+	return api.Diff(copyZone(z), desired), nil
+}
+
+func (s zones) Apply(ctx context.Context, p api.Plan) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.Apply"); err != nil {
+		return err
+	}
+	if p.Empty() {
+		return nil
+	}
+	// plans are validated when they are created
+	return s.f.apply(p)
+}
+
+func (s zones) SOA(ctx context.Context, name string) (api.SOAData, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.SOA"); err != nil {
+		return api.SOAData{}, err
+	}
+	z, ok := s.f.zones[name]
+	if !ok {
+		return api.SOAData{}, errNotFound()
+	}
+	r := z.Find("@", api.RecordTypeSOA)
+	if r == nil {
+		return api.SOAData{}, api.ErrNoSOA
+	}
+	v, err := r.Parse()
+	if err != nil {
+		return api.SOAData{}, err
+	}
+	return v.(api.SOAData), nil
+}
+
+func (s zones) UpdateSOA(ctx context.Context, z api.Zone, u api.SOAUpdate) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Zones.UpdateSOA"); err != nil {
+		return err
+	}
+	stored, ok := s.f.zones[z.Name]
+	if !ok {
+		return errNotFound()
+	}
+	p, err := api.PlanSOA(ctx, copyZone(stored), u)
+	if err != nil {
+		return err
+	}
+	// the SOA was validated by PlanSOA
+	return s.f.apply(p)
+}
+
+type records struct {
+	f *Fake
+}
+
+func (s records) Info(ctx context.Context, id int) (api.Record, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Records.Info"); err != nil {
+		return api.Record{}, err
+	}
+	for _, z := range s.f.zones {
+		for _, r := range z.Records {
+			if r.ID == id {
+				return *r, nil
+			}
+		}
+	}
+	return api.Record{}, errNotFound()
+}
+
+// mutate applies a single action on the zone
+func (s records) mutate(ctx context.Context, op string, z api.Zone, t api.MutationType, r api.Record) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call(op); err != nil {
+		return err
+	}
+	// removals are never validated, other records only against the zone
+	// of the caller like ZoneRecordAdd and ZoneRecordChange
+	if !api.ValidationSkipped(ctx) && t != api.Remove {
+		if err := z.ValidateRecord(r); err != nil {
+			return err
+		}
+	}
+	return s.f.apply(api.Plan{Zone: z.Name, Actions: []api.PlanAction{{Type: t, Record: r}}})
+}
+
+func (s records) Add(ctx context.Context, z api.Zone, r api.Record) error {
+	return s.mutate(ctx, "Records.Add", z, api.Add, r)
+}
+
+func (s records) Change(ctx context.Context, z api.Zone, r api.Record) error {
+	return s.mutate(ctx, "Records.Change", z, api.Change, r)
+}
+
+func (s records) Delete(ctx context.Context, z api.Zone, r api.Record) error {
+	return s.mutate(ctx, "Records.Delete", z, api.Remove, r)
+}
+
+func (s records) Upsert(ctx context.Context, z api.Zone, r api.Record, match api.Matcher) (api.MutationType, error) {
+	s.f.mu.Lock()
+	err := s.f.call("Records.Upsert")
+	s.f.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	p, t, err := api.PlanUpsert(z, r, match)
+	if err != nil {
+		return "", err
+	}
+
+	// the record is added or changed like the api package does, so the
+	// calls of both operations are recorded
+	switch t {
+	case api.Add:
+		return t, s.Add(ctx, z, p.Actions[0].Record)
+	case api.Change:
+		return t, s.Change(ctx, z, p.Actions[0].Record)
+	}
+	return t, nil
+}
+
+func (s records) ReplaceRRSet(ctx context.Context, z api.Zone, host string, t api.RecordType, records []api.Record) (api.Plan, error) {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.call("Records.ReplaceRRSet"); err != nil {
+		return api.Plan{}, err
+	}
+	p, err := api.PlanRRSet(ctx, z, host, t, records)
+	if err != nil || p.Empty() {
+		return p, err
+	}
+	return p, s.f.apply(p)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package apitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+func testFake() *Fake {
+	f := New()
+	f.AddDomain("example.net")
+	f.AddZone(api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{Host: "@", Type: api.RecordTypeSOA, Data: "ns1.eurodns.com.", RespPerson: "hostmaster.example.org.", Refresh: 3600, Retry: 600, Expire: 1209600, TTL: 3600},
+			{Host: "www", Type: api.RecordTypeA, Data: "192.0.2.1", TTL: 3600},
+		},
+	})
+	return f
+}
+
+func TestLists(t *testing.T) {
+	s := testFake().Services()
+	ctx := context.TODO()
+
+	domains, err := s.Domains.List(ctx)
+	if err != nil || len(domains) != 2 {
+		t.Errorf("unexpected domains %v: %v", domains, err)
+	}
+	zones, err := s.Zones.List(ctx)
+	if err != nil || len(zones) != 1 || zones[0] != "example.org" {
+		t.Errorf("unexpected zones %v: %v", zones, err)
+	}
+
+	_, err = s.Zones.Info(ctx, "example.com")
+	if e, ok := err.(*eurodnsgo.Error); !ok || e.Code != codeNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	f := testFake()
+	s := f.Services()
+	ctx := context.TODO()
+
+	z, _ := s.Zones.Info(ctx, "example.org")
+	if err := s.Records.Add(ctx, z, api.Record{Host: "mail", Type: api.RecordTypeA, Data: "192.0.2.2", TTL: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Records.Add(ctx, z, api.Record{Host: "www", Type: api.RecordTypeCNAME, Data: "mail", TTL: 3600}); err == nil {
+		t.Error("expected a CNAME conflicting with the A record to be rejected")
+	}
+
+	z, _ = s.Zones.Info(ctx, "example.org")
+	mail := z.Find("mail", api.RecordTypeA)
+	if mail == nil || mail.ID == 0 {
+		t.Fatalf("expected the added record with an ID, got %+v", mail)
+	}
+	if r, err := s.Records.Info(ctx, mail.ID); err != nil || r.Data != "192.0.2.2" {
+		t.Errorf("unexpected record %+v: %v", r, err)
+	}
+
+	mt, err := s.Records.Upsert(ctx, z, api.Record{Host: "mail", Type: api.RecordTypeA, Data: "192.0.2.3", TTL: 3600}, nil)
+	if err != nil || mt != api.Change {
+		t.Errorf("expected a change, got %s: %v", mt, err)
+	}
+
+	z, _ = s.Zones.Info(ctx, "example.org")
+	p, err := s.Records.ReplaceRRSet(ctx, z, "www", api.RecordTypeA, []api.Record{{Data: "192.0.2.10", TTL: 3600}, {Data: "192.0.2.11", TTL: 3600}})
+	if err != nil || len(p.Actions) != 2 {
+		t.Errorf("unexpected plan %s: %v", p, err)
+	}
+
+	z, _ = s.Zones.Info(ctx, "example.org")
+	if err := s.Records.Delete(ctx, z, *z.Find("mail", api.RecordTypeA)); err != nil {
+		t.Fatal(err)
+	}
+
+	z, _ = f.Zone("example.org")
+	if len(z.Records) != 3 || len(z.FindAll("www", api.RecordTypeA)) != 2 {
+		t.Errorf("unexpected records %+v", z.Records)
+	}
+}
+
+func TestRecordsInvalidZone(t *testing.T) {
+	f := testFake()
+	s := f.Services()
+	ctx := context.TODO()
+
+	// records accepted by the API once do not block changes of others
+	f.AddZone(api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{Host: "www", Type: api.RecordTypeA, Data: "192.0.2.1", TTL: 30},
+			{Host: "mail", Type: api.RecordTypeA, Data: "192.0.2.2", TTL: 3600},
+		},
+	})
+
+	z, _ := s.Zones.Info(ctx, "example.org")
+	if err := s.Records.Delete(ctx, z, *z.Find("mail", api.RecordTypeA)); err != nil {
+		t.Errorf("expected the removal not to be validated, got %v", err)
+	}
+	if err := s.Records.Add(ctx, z, api.Record{Host: "ftp", Type: api.RecordTypeA, Data: "192.0.2.3", TTL: 3600}); err != nil {
+		t.Errorf("expected only the added record to be validated, got %v", err)
+	}
+	if err := s.Records.Add(ctx, z, api.Record{Host: "ftp", Type: api.RecordTypeA, Data: "192.0.2.4", TTL: 30}); err == nil {
+		t.Error("expected an invalid record to be rejected")
+	}
+}
+
+func TestZonePlanAndSOA(t *testing.T) {
+	s := testFake().Services()
+	ctx := context.TODO()
+
+	desired, _ := s.Zones.Info(ctx, "example.org")
+	desired.Records[1].Data = "192.0.2.5"
+	p, err := s.Zones.Plan(ctx, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Zones.Apply(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := s.Zones.Plan(ctx, desired); !p.Empty() {
+		t.Errorf("expected the zone to be in sync, got\n%s", p)
+	}
+
+	retry := 7200
+	if err := s.Zones.UpdateSOA(ctx, desired, api.SOAUpdate{Retry: &retry}); err == nil {
+		t.Error("expected a retry above the refresh to be rejected")
+	}
+	retry = 900
+	if err := s.Zones.UpdateSOA(ctx, desired, api.SOAUpdate{Retry: &retry}); err != nil {
+		t.Fatal(err)
+	}
+	if soa, err := s.Zones.SOA(ctx, "example.org"); err != nil || soa.Retry != 900 {
+		t.Errorf("unexpected SOA %+v: %v", soa, err)
+	}
+}
+
+func TestFail(t *testing.T) {
+	f := testFake()
+	s := f.Services()
+	ctx := context.TODO()
+
+	failure := errors.New("failure")
+	f.Fail("Zones.List", failure)
+	if _, err := s.Zones.List(ctx); err != failure {
+		t.Errorf("expected the injected error, got %v", err)
+	}
+	f.Fail("Zones.List", nil)
+	if _, err := s.Zones.List(ctx); err != nil {
+		t.Errorf("expected the error to be cleared, got %v", err)
+	}
+
+	if calls := f.Calls(); len(calls) != 2 || calls[0] != "Zones.List" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+}
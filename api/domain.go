@@ -18,8 +18,31 @@ var (
 	RecordTypeNS RecordType = "NS"
 	// RecordTypeTXT represents a TXT-record
 	RecordTypeTXT RecordType = "TXT"
+	// RecordTypeSPF represents an SPF-record, which holds the same data as
+	// a TXT-record
+	RecordTypeSPF RecordType = "SPF"
 	// RecordTypeSRV represents an SRV-record
 	RecordTypeSRV RecordType = "SRV"
+	// RecordTypeSOA represents the SOA-record of a zone
+	RecordTypeSOA RecordType = "SOA"
+	// RecordTypeCAA represents a CAA-record
+	RecordTypeCAA RecordType = "CAA"
+	// RecordTypePTR represents a PTR-record
+	RecordTypePTR RecordType = "PTR"
+	// RecordTypeTLSA represents a TLSA-record
+	RecordTypeTLSA RecordType = "TLSA"
+	// RecordTypeSSHFP represents an SSHFP-record
+	RecordTypeSSHFP RecordType = "SSHFP"
+	// RecordTypeNAPTR represents a NAPTR-record
+	RecordTypeNAPTR RecordType = "NAPTR"
+	// RecordTypeDS represents a DS-record
+	RecordTypeDS RecordType = "DS"
+	// RecordTypeALIAS represents a provider specific ALIAS-record, which
+	// behaves like a CNAME-record but is allowed at the zone apex
+	RecordTypeALIAS RecordType = "ALIAS"
+	// RecordTypeURL represents a provider specific URL-record, which
+	// redirects web traffic for the host to the URL in its data
+	RecordTypeURL RecordType = "URL"
 )
 
 // Record represents an EuroDNS Record object
@@ -41,8 +64,10 @@ type Record struct {
 // Zone represents an EuroDNS Zone object
 // See https://agent.api-eurodns.com/doc/zone/info
 type Zone struct {
-	XMLName xml.Name  `xml:"zone,omitempty"`
-	Name    string    `xml:"zone name"`
+	XMLName xml.Name `xml:"zone,omitempty"`
+	Name    string   `xml:"zone name"`
+	// Records should not hold nil records, which are reported by Validate
+	// and skipped by the other methods and functions of the package
 	Records []*Record `xml:"zone records>record,omitempty"`
 }
 
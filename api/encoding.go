@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ZoneSchemaVersion is the version of the JSON and YAML representation of
+// a Zone. It is written with every zone and checked when reading one.
+const ZoneSchemaVersion = 1
+
+type zoneDoc struct {
+	Version int       `json:"version" yaml:"version"`
+	Name    string    `json:"name" yaml:"name"`
+	Records []*Record `json:"records,omitempty" yaml:"records,omitempty"`
+}
+
+type recordDoc struct {
+	ID       int        `json:"id,omitempty" yaml:"id,omitempty"`
+	Host     string     `json:"host" yaml:"host"`
+	Type     RecordType `json:"type" yaml:"type"`
+	TTL      int        `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Priority int        `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Data     string     `json:"data,omitempty" yaml:"data,omitempty"`
+	SOA      *soaDoc    `json:"soa,omitempty" yaml:"soa,omitempty"`
+}
+
+type soaDoc struct {
+	RespPerson string `json:"resp_person,omitempty" yaml:"resp_person,omitempty"`
+	Refresh    int    `json:"refresh,omitempty" yaml:"refresh,omitempty"`
+	Retry      int    `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Expire     int    `json:"expire,omitempty" yaml:"expire,omitempty"`
+}
+
+func (r Record) doc() recordDoc {
+	d := recordDoc{
+		ID:   r.ID,
+		Host: r.Host,
+		Type: r.Type,
+		TTL:  r.TTL,
+		Data: r.Data,
+	}
+	switch r.Type {
+	case RecordTypeMX, RecordTypeSRV:
+		d.Priority = r.Priority
+	case RecordTypeSOA:
+		d.SOA = &soaDoc{r.RespPerson, r.Refresh, r.Retry, r.Expire}
+	}
+	return d
+}
+
+func (r *Record) fromDoc(d recordDoc) {
+	*r = Record{
+		ID:       d.ID,
+		Host:     d.Host,
+		Type:     d.Type,
+		TTL:      d.TTL,
+		Priority: d.Priority,
+		Data:     d.Data,
+	}
+	if d.SOA != nil {
+		r.RespPerson = d.SOA.RespPerson
+		r.Refresh = d.SOA.Refresh
+		r.Retry = d.SOA.Retry
+		r.Expire = d.SOA.Expire
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Fields which do not apply to the
+// record type and zero values are omitted.
+func (r Record) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.doc())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *Record) UnmarshalJSON(b []byte) error {
+	var d recordDoc
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	r.fromDoc(d)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (r Record) MarshalYAML() (interface{}, error) {
+	return r.doc(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (r *Record) UnmarshalYAML(n *yaml.Node) error {
+	var d recordDoc
+	if err := n.Decode(&d); err != nil {
+		return err
+	}
+	r.fromDoc(d)
+	return nil
+}
+
+func (z *Zone) fromDoc(d zoneDoc) error {
+	if d.Version > ZoneSchemaVersion {
+		return fmt.Errorf("unsupported zone schema version %d", d.Version)
+	}
+	*z = Zone{Name: d.Name, Records: d.Records}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (z Zone) MarshalJSON() ([]byte, error) {
+	return json.Marshal(zoneDoc{ZoneSchemaVersion, z.Name, z.Records})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Documents without a version
+// are read as the current schema version.
+func (z *Zone) UnmarshalJSON(b []byte) error {
+	var d zoneDoc
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	return z.fromDoc(d)
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (z Zone) MarshalYAML() (interface{}, error) {
+	return zoneDoc{ZoneSchemaVersion, z.Name, z.Records}, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. Documents without a version
+// are read as the current schema version.
+func (z *Zone) UnmarshalYAML(n *yaml.Node) error {
+	var d zoneDoc
+	if err := n.Decode(&d); err != nil {
+		return err
+	}
+	return z.fromDoc(d)
+}
+
+// LoadZone reads a Zone from a JSON or YAML file, the format is selected
+// by the .json, .yaml or .yml file extension
+func LoadZone(path string) (Zone, error) {
+	var z Zone
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return z, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, &z)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &z)
+	default:
+		err = fmt.Errorf("unsupported zone file format %q", filepath.Ext(path))
+	}
+
+	return z, err
+}
+
+// SaveZone writes a Zone to a JSON or YAML file, the format is selected
+// by the .json, .yaml or .yml file extension
+func SaveZone(path string, z Zone) error {
+	var b []byte
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		b, err = json.MarshalIndent(z, "", "  ")
+		b = append(b, '\n')
+	case ".yaml", ".yml":
+		b, err = yaml.Marshal(z)
+	default:
+		err = fmt.Errorf("unsupported zone file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0644)
+}
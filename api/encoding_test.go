@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var encodingZone = Zone{
+	Name: "example.org",
+	Records: []*Record{
+		{ID: 1, Host: "@", Type: RecordTypeSOA, TTL: 3600, Data: "ns1.example.org.", RespPerson: "hostmaster.example.org.", Refresh: 10800, Retry: 3600, Expire: 1209600},
+		{ID: 2, Host: "@", Type: RecordTypeMX, TTL: 3600, Priority: 10, Data: "mail.example.org."},
+		{ID: 3, Host: "www", Type: RecordTypeA, Data: "192.0.2.1"},
+	},
+}
+
+func TestRecordJSON(t *testing.T) {
+	b, err := json.Marshal(encodingZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := `{"version":1,"name":"example.org","records":[` +
+		`{"id":1,"host":"@","type":"SOA","ttl":3600,"data":"ns1.example.org.","soa":{"resp_person":"hostmaster.example.org.","refresh":10800,"retry":3600,"expire":1209600}},` +
+		`{"id":2,"host":"@","type":"MX","ttl":3600,"priority":10,"data":"mail.example.org."},` +
+		`{"id":3,"host":"www","type":"A","data":"192.0.2.1"}]}`
+	if string(b) != e {
+		t.Errorf("unexpected JSON\nexpected %s\nreceived %s", e, b)
+	}
+
+	var z Zone
+	if err := json.Unmarshal(b, &z); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(z, encodingZone) {
+		t.Errorf("JSON round trip mismatch: %+v", z)
+	}
+}
+
+func TestRecordYAML(t *testing.T) {
+	b, err := yaml.Marshal(encodingZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var z Zone
+	if err := yaml.Unmarshal(b, &z); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(z, encodingZone) {
+		t.Errorf("YAML round trip mismatch:\n%s", b)
+	}
+}
+
+func TestZoneSchemaVersion(t *testing.T) {
+	var z Zone
+	if err := json.Unmarshal([]byte(`{"version":99,"name":"example.org"}`), &z); err == nil {
+		t.Error("expected newer schema versions to be rejected")
+	}
+	if err := json.Unmarshal([]byte(`{"name":"example.org"}`), &z); err != nil || z.Name != "example.org" {
+		t.Errorf("expected unversioned documents to be accepted, got %v", err)
+	}
+}
+
+func TestLoadSaveZone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eurodnsgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"zone.json", "zone.yaml"} {
+		path := filepath.Join(dir, name)
+		if err := SaveZone(path, encodingZone); err != nil {
+			t.Fatal(err)
+		}
+		z, err := LoadZone(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(z, encodingZone) {
+			t.Errorf("%s: round trip mismatch: %+v", name, z)
+		}
+	}
+
+	if err := SaveZone(filepath.Join(dir, "zone.txt"), encodingZone); err == nil {
+		t.Error("expected unknown extensions to be rejected")
+	}
+}
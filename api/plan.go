@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// Plan describes the mutations needed to bring a live zone in line with a
+// desired state. Plans are created by PlanZone or Diff and executed by
+// ApplyPlan.
+type Plan struct {
+	Zone    string       `json:"zone"`
+	Actions []PlanAction `json:"actions"`
+}
+
+// PlanAction is a single mutation inside a Plan
+type PlanAction struct {
+	Type MutationType `json:"type"`
+	// Record holds the desired state. For Change and Remove actions the ID
+	// refers to the live record.
+	Record Record `json:"record"`
+	// Current holds the live record for Change and Remove actions
+	Current *Record `json:"current,omitempty"`
+}
+
+// Empty reports whether the plan contains no actions
+func (p Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+// Records returns the records of all actions of the given MutationType
+func (p Plan) Records(t MutationType) []Record {
+	var res []Record
+	for _, a := range p.Actions {
+		if a.Type == t {
+			res = append(res, a.Record)
+		}
+	}
+	return res
+}
+
+// String returns a human-readable summary of the plan
+func (p Plan) String() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "zone %s: %d to add, %d to change, %d to remove\n", p.Zone,
+		len(p.Records(Add)), len(p.Records(Change)), len(p.Records(Remove)))
+	for _, a := range p.Actions {
+		switch a.Type {
+		case Add:
+			fmt.Fprintf(&buf, "  + %s\n", formatRecord(a.Record))
+		case Change:
+			if a.Current == nil {
+				fmt.Fprintf(&buf, "  ~ %s\n", formatRecord(a.Record))
+				continue
+			}
+			fmt.Fprintf(&buf, "  ~ %s (was %s)\n", formatRecord(a.Record), formatRecord(*a.Current))
+		case Remove:
+			fmt.Fprintf(&buf, "  - %s\n", formatRecord(a.Record))
+		}
+	}
+	return buf.String()
+}
+
+func formatRecord(r Record) string {
+	switch r.Type {
+	case RecordTypeMX, RecordTypeSRV:
+		return fmt.Sprintf("%s %d %s %d %s", r.Host, r.TTL, r.Type, r.Priority, r.Data)
+	}
+	return fmt.Sprintf("%s %d %s %s", r.Host, r.TTL, r.Type, r.Data)
+}
+
+func sameRRSet(zone string, a, b *Record) bool {
+	return normalizeHost(zone, a.Host) == normalizeHost(zone, b.Host) &&
+		strings.EqualFold(string(a.Type), string(b.Type))
+}
+
+func sameData(zone string, a, b *Record) bool {
+	return sameRRSet(zone, a, b) && strings.TrimSpace(a.Data) == strings.TrimSpace(b.Data)
+}
+
+// recordEqual compares all properties of a live record a and a desired
+// record b except their ID. A desired TTL of 0 leaves the TTL to EuroDNS,
+// so it matches any live TTL.
+func recordEqual(zone string, a, b *Record) bool {
+	return sameData(zone, a, b) &&
+		(b.TTL == 0 || a.TTL == b.TTL) &&
+		a.Priority == b.Priority &&
+		a.Expire == b.Expire &&
+		a.Refresh == b.Refresh &&
+		a.Retry == b.Retry &&
+		a.RespPerson == b.RespPerson
+}
+
+// Diff compares the records of a live zone with the desired state and
+// returns the Plan to get from one to the other.
+//
+// Desired records are matched against live records in three passes: first
+// by ID, then on host, type and data, and finally on host and type alone.
+// Matched records which differ become changes of the live record, unmatched
+// desired records are added and unmatched live records are removed. SOA
+// records are never removed. Desired records without a TTL keep the TTL of
+// the live record. Nil records are skipped.
+func Diff(live, desired Zone) Plan {
+	p := Plan{Zone: live.Name}
+	if p.Zone == "" {
+		p.Zone = desired.Name
+	}
+	live.Records = live.Filter(anyRecord)
+	desired.Records = desired.Filter(anyRecord)
+
+	matched := make([]*Record, len(desired.Records))
+	used := make(map[*Record]bool)
+	match := func(fn func(l, d *Record) bool) {
+		for i, d := range desired.Records {
+			if matched[i] != nil {
+				continue
+			}
+			for _, l := range live.Records {
+				if !used[l] && fn(l, d) {
+					matched[i] = l
+					used[l] = true
+					break
+				}
+			}
+		}
+	}
+	match(func(l, d *Record) bool { return d.ID != 0 && l.ID == d.ID })
+	match(func(l, d *Record) bool { return sameData(p.Zone, l, d) })
+	match(func(l, d *Record) bool { return sameRRSet(p.Zone, l, d) })
+
+	for i, d := range desired.Records {
+		l := matched[i]
+		if l == nil || recordEqual(p.Zone, l, d) {
+			continue
+		}
+		r := *d
+		r.ID = l.ID
+		p.Actions = append(p.Actions, PlanAction{Type: Change, Record: r, Current: l})
+	}
+	for i, d := range desired.Records {
+		if matched[i] != nil {
+			continue
+		}
+		r := *d
+		r.ID = 0
+		p.Actions = append(p.Actions, PlanAction{Type: Add, Record: r})
+	}
+	for _, l := range live.Records {
+		if used[l] || l.Type == RecordTypeSOA {
+			continue
+		}
+		p.Actions = append(p.Actions, PlanAction{Type: Remove, Record: *l, Current: l})
+	}
+	return p
+}
+
+// PlanZone fetches the live state of the desired zone and returns the Plan
+// needed to make it match the desired records. The desired zone is validated
+// first, unless the context was created by SkipValidation.
+func PlanZone(ctx context.Context, c eurodnsgo.Client, desired Zone) (Plan, error) {
+	if !ValidationSkipped(ctx) {
+		if err := desired.Validate(); err != nil {
+			return Plan{}, err
+		}
+	}
+
+	ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns api.PlanZone", zoneAttr(desired.Name))
+	live, err := GetZoneInfo(ctx, c, desired.Name)
+	eurodnsgo.EndSpan(span, err)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return Diff(live, desired), nil
+}
+
+// ApplyPlan executes all actions of a Plan in a single zone:update request
+func ApplyPlan(ctx context.Context, c eurodnsgo.Client, p Plan) error {
+	if p.Empty() {
+		return nil
+	}
+
+	var v interface{}
+	sr, err := zoneUpdateRequest(v, Zone{Name: p.Zone}, p.Records(Add), p.Records(Change), p.Records(Remove))
+	if err != nil {
+		return err
+	}
+
+	ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns api.ApplyPlan", zoneAttr(p.Zone),
+		eurodnsgo.Attr{Key: "eurodns.plan.add", Value: len(p.Records(Add))},
+		eurodnsgo.Attr{Key: "eurodns.plan.change", Value: len(p.Records(Change))},
+		eurodnsgo.Attr{Key: "eurodns.plan.remove", Value: len(p.Records(Remove))},
+	)
+	err = schedule(ctx, c, sr)
+	eurodnsgo.EndSpan(span, err)
+
+	return err
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/omines/eurodnsgo"
+)
+
+func TestDiff(t *testing.T) {
+	live := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "@", Type: RecordTypeSOA, Data: "ns1.example.org."},
+			{ID: 2, Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+			{ID: 3, Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.2"},
+			{ID: 4, Host: "mail", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.10"},
+			{ID: 5, Host: "old", Type: RecordTypeCNAME, TTL: 3600, Data: "www.example.org."},
+		},
+	}
+	desired := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+			{Host: "WWW.example.org.", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.3"},
+			{Host: "mail", Type: RecordTypeA, TTL: 300, Data: "192.0.2.10"},
+			{Host: "@", Type: RecordTypeMX, TTL: 3600, Priority: 10, Data: "mail.example.org."},
+		},
+	}
+
+	p := Diff(live, desired)
+	if p.Zone != "example.org" {
+		t.Errorf("expected plan for example.org, got %s", p.Zone)
+	}
+
+	changes := p.Records(Change)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(changes))
+	}
+	if changes[0].ID != 3 || changes[0].Data != "192.0.2.3" {
+		t.Errorf("expected record 3 to change to 192.0.2.3, got %+v", changes[0])
+	}
+	if changes[1].ID != 4 || changes[1].TTL != 300 {
+		t.Errorf("expected TTL change of record 4, got %+v", changes[1])
+	}
+
+	adds := p.Records(Add)
+	if len(adds) != 1 || adds[0].Type != RecordTypeMX {
+		t.Errorf("expected the MX record to be added, got %+v", adds)
+	}
+
+	removes := p.Records(Remove)
+	if len(removes) != 1 || removes[0].ID != 5 {
+		t.Errorf("expected only record 5 to be removed, got %+v", removes)
+	}
+
+	if s := p.String(); !strings.HasPrefix(s, "zone example.org: 1 to add, 2 to change, 1 to remove") {
+		t.Errorf("unexpected plan summary %q", s)
+	}
+}
+
+func TestPlanStringWithoutCurrent(t *testing.T) {
+	p := Plan{
+		Zone: "example.org",
+		Actions: []PlanAction{
+			{Type: Change, Record: Record{ID: 1, Host: "www", Type: RecordTypeA, TTL: 300, Data: "192.0.2.1"}},
+		},
+	}
+
+	if s := p.String(); !strings.Contains(s, "  ~ www 300 A 192.0.2.1\n") {
+		t.Errorf("unexpected plan summary %q", s)
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+		},
+	}
+
+	if p := Diff(z, z); !p.Empty() {
+		t.Errorf("expected an empty plan, got %s", p)
+	}
+}
+
+func TestDiffDefaultTTL(t *testing.T) {
+	live := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+		},
+	}
+
+	desired := Zone{Name: "example.org", Records: []*Record{
+		{Host: "www", Type: RecordTypeA, Data: "192.0.2.1"},
+	}}
+	if p := Diff(live, desired); !p.Empty() {
+		t.Errorf("expected a TTL of 0 to match the live TTL, got %s", p)
+	}
+
+	desired.Records[0].TTL = 300
+	if p := Diff(live, desired); len(p.Records(Change)) != 1 {
+		t.Errorf("expected a change of the TTL, got %s", p)
+	}
+}
+
+func TestApplyPlanRequest(t *testing.T) {
+	e := `<?xml version="1.0" encoding="UTF-8"?>
+<request xmlns:zone="http://www.eurodns.com/zone">
+	<zone:update><zone:name>zone</zone:name><zone:records><zone:add><zone:record><record:data>192.0.2.1</record:data><record:expire>0</record:expire><record:host>a</record:host><record:priority>0</record:priority><record:refresh>0</record:refresh><record:resp_person></record:resp_person><record:retry>0</record:retry><record:ttl>0</record:ttl><record:type>A</record:type></zone:record><zone:record><record:data>192.0.2.2</record:data><record:expire>0</record:expire><record:host>b</record:host><record:priority>0</record:priority><record:refresh>0</record:refresh><record:resp_person></record:resp_person><record:retry>0</record:retry><record:ttl>0</record:ttl><record:type>A</record:type></zone:record></zone:add><zone:remove><zone:record id="12"></zone:record></zone:remove></zone:records></zone:update>
+</request>`
+	var v interface{}
+	sr, err := zoneUpdateRequest(v, Zone{Name: "zone"}, []Record{
+		{Host: "a", Type: RecordTypeA, Data: "192.0.2.1"},
+		{Host: "b", Type: RecordTypeA, Data: "192.0.2.2"},
+	}, nil, []Record{{ID: 12}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testParams(t, sr, e)
+}
+
+// spanTracer keeps the attributes of all started spans by name
+type spanTracer map[string][]eurodnsgo.Attr
+
+func (t spanTracer) Start(ctx context.Context, name string) (context.Context, eurodnsgo.Span) {
+	t[name] = nil
+	return ctx, spanRecorder{t, name}
+}
+
+type spanRecorder struct {
+	t    spanTracer
+	name string
+}
+
+func (s spanRecorder) SetAttributes(attrs ...eurodnsgo.Attr) {
+	s.t[s.name] = append(s.t[s.name], attrs...)
+}
+func (s spanRecorder) RecordError(err error) {}
+func (s spanRecorder) End()                  {}
+
+// tracedClient is a testClient with a Tracer
+type tracedClient struct {
+	testClient
+	tracer eurodnsgo.Tracer
+}
+
+func (c *tracedClient) Tracer() eurodnsgo.Tracer {
+	return c.tracer
+}
+
+func TestApplyPlanSpan(t *testing.T) {
+	tracer := spanTracer{}
+
+	p := Plan{Zone: "example.org", Actions: []PlanAction{
+		{Type: Add, Record: Record{Host: "www", Type: RecordTypeA, Data: "192.0.2.1"}},
+	}}
+	if err := ApplyPlan(context.TODO(), &tracedClient{tracer: tracer}, p); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, ok := tracer["eurodns api.ApplyPlan"]
+	if !ok {
+		t.Fatal("expected a span for the plan")
+	}
+	if len(attrs) != 4 || attrs[0].Value != "example.org" || attrs[1].Value != 1 {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
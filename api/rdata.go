@@ -0,0 +1,509 @@
+package api
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CAAData holds the data of a CAA record as defined by RFC 8659
+type CAAData struct {
+	Flag  uint8
+	Tag   string
+	Value string
+}
+
+// String returns the data as used in Record.Data
+func (d CAAData) String() string {
+	return fmt.Sprintf("%d %s %s", d.Flag, d.Tag, quote(d.Value))
+}
+
+// ParseCAA parses the data of a CAA record
+func ParseCAA(data string) (CAAData, error) {
+	var d CAAData
+	f, err := fields(data, 3)
+	if err != nil {
+		return d, fmt.Errorf("invalid CAA data %q: %s", data, err)
+	}
+
+	flag, err := strconv.ParseUint(f[0], 10, 8)
+	if err != nil {
+		return d, fmt.Errorf("invalid CAA flag %q", f[0])
+	}
+	if f[1] == "" || !isAlphaNumeric(f[1]) {
+		return d, fmt.Errorf("invalid CAA tag %q", f[1])
+	}
+
+	return CAAData{uint8(flag), f[1], f[2]}, nil
+}
+
+// TLSAData holds the data of a TLSA record as defined by RFC 6698
+type TLSAData struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	// Certificate holds the hexadecimal certificate association data
+	Certificate string
+}
+
+// String returns the data as used in Record.Data
+func (d TLSAData) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.Usage, d.Selector, d.MatchingType, d.Certificate)
+}
+
+// ParseTLSA parses the data of a TLSA record
+func ParseTLSA(data string) (TLSAData, error) {
+	var d TLSAData
+	f, err := hexFields(data, 4)
+	if err != nil {
+		return d, fmt.Errorf("invalid TLSA data %q: %s", data, err)
+	}
+
+	n, err := parseUint8s(f[:3])
+	if err != nil {
+		return d, fmt.Errorf("invalid TLSA data %q: %s", data, err)
+	}
+	if !isHex(f[3]) {
+		return d, fmt.Errorf("invalid TLSA certificate data %q", f[3])
+	}
+
+	return TLSAData{n[0], n[1], n[2], f[3]}, nil
+}
+
+// SSHFPData holds the data of an SSHFP record as defined by RFC 4255
+type SSHFPData struct {
+	Algorithm uint8
+	Type      uint8
+	// Fingerprint holds the hexadecimal fingerprint of the key
+	Fingerprint string
+}
+
+// String returns the data as used in Record.Data
+func (d SSHFPData) String() string {
+	return fmt.Sprintf("%d %d %s", d.Algorithm, d.Type, d.Fingerprint)
+}
+
+// ParseSSHFP parses the data of an SSHFP record
+func ParseSSHFP(data string) (SSHFPData, error) {
+	var d SSHFPData
+	f, err := hexFields(data, 3)
+	if err != nil {
+		return d, fmt.Errorf("invalid SSHFP data %q: %s", data, err)
+	}
+
+	n, err := parseUint8s(f[:2])
+	if err != nil {
+		return d, fmt.Errorf("invalid SSHFP data %q: %s", data, err)
+	}
+	if !isHex(f[2]) {
+		return d, fmt.Errorf("invalid SSHFP fingerprint %q", f[2])
+	}
+
+	return SSHFPData{n[0], n[1], f[2]}, nil
+}
+
+// NAPTRData holds the data of a NAPTR record as defined by RFC 3403
+type NAPTRData struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Service     string
+	Regexp      string
+	Replacement string
+}
+
+// String returns the data as used in Record.Data
+func (d NAPTRData) String() string {
+	return fmt.Sprintf("%d %d %s %s %s %s", d.Order, d.Preference,
+		quote(d.Flags), quote(d.Service), quote(d.Regexp), d.Replacement)
+}
+
+// ParseNAPTR parses the data of a NAPTR record
+func ParseNAPTR(data string) (NAPTRData, error) {
+	var d NAPTRData
+	f, err := fields(data, 6)
+	if err != nil {
+		return d, fmt.Errorf("invalid NAPTR data %q: %s", data, err)
+	}
+
+	order, err := strconv.ParseUint(f[0], 10, 16)
+	if err != nil {
+		return d, fmt.Errorf("invalid NAPTR order %q", f[0])
+	}
+	pref, err := strconv.ParseUint(f[1], 10, 16)
+	if err != nil {
+		return d, fmt.Errorf("invalid NAPTR preference %q", f[1])
+	}
+	if f[5] != "." && !validDomainName(f[5]) {
+		return d, fmt.Errorf("invalid NAPTR replacement %q", f[5])
+	}
+
+	return NAPTRData{uint16(order), uint16(pref), f[2], f[3], f[4], f[5]}, nil
+}
+
+// DSData holds the data of a DS record as defined by RFC 4034
+type DSData struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	// Digest holds the hexadecimal digest of the DNSKEY
+	Digest string
+}
+
+// String returns the data as used in Record.Data
+func (d DSData) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// ParseDS parses the data of a DS record
+func ParseDS(data string) (DSData, error) {
+	var d DSData
+	f, err := hexFields(data, 4)
+	if err != nil {
+		return d, fmt.Errorf("invalid DS data %q: %s", data, err)
+	}
+
+	tag, err := strconv.ParseUint(f[0], 10, 16)
+	if err != nil {
+		return d, fmt.Errorf("invalid DS key tag %q", f[0])
+	}
+	n, err := parseUint8s(f[1:3])
+	if err != nil {
+		return d, fmt.Errorf("invalid DS data %q: %s", data, err)
+	}
+	if !isHex(f[3]) {
+		return d, fmt.Errorf("invalid DS digest %q", f[3])
+	}
+
+	return DSData{uint16(tag), n[0], n[1], f[3]}, nil
+}
+
+// fields splits record data on whitespace, honouring double quoted
+// strings, and verifies that exactly n fields are present
+func fields(data string, n int) ([]string, error) {
+	f, err := splitQuoted(data)
+	if err != nil {
+		return nil, err
+	}
+	return checkFields(f, n)
+}
+
+// hexFields splits record data like fields, for record types which end in
+// hexadecimal data. That data may be split up by spaces, which are removed.
+func hexFields(data string, n int) ([]string, error) {
+	f, err := splitQuoted(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(f) > n && isHex(strings.Join(f[n-1:], "")) {
+		f = append(f[:n-1], strings.Join(f[n-1:], ""))
+	}
+	return checkFields(f, n)
+}
+
+func checkFields(f []string, n int) ([]string, error) {
+	if len(f) != n {
+		return nil, fmt.Errorf("expected %d fields, found %d", n, len(f))
+	}
+	return f, nil
+}
+
+// splitQuoted splits a string on whitespace and returns the fields with
+// surrounding double quotes removed and escape sequences resolved
+func splitQuoted(s string) ([]string, error) {
+	var res []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ' ', '\t':
+			continue
+		case '"':
+			var text []byte
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				text = append(text, s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+			res = append(res, string(text))
+		default:
+			start := i
+			for i+1 < len(s) && s[i+1] != ' ' && s[i+1] != '\t' {
+				i++
+			}
+			res = append(res, s[start:i+1])
+		}
+	}
+	return res, nil
+}
+
+// quote returns s as a double quoted string
+func quote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+}
+
+func parseUint8s(f []string) ([]uint8, error) {
+	res := make([]uint8, len(f))
+	for i, s := range f {
+		n, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number between 0 and 255", s)
+		}
+		res[i] = uint8(n)
+	}
+	return res, nil
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return s != "" && err == nil
+}
+
+func isAlphaNumeric(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func validateCAA(r Record) string {
+	if _, err := ParseCAA(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateTLSA(r Record) string {
+	labels := strings.Split(normalizeHost("", r.Host), ".")
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return "TLSA hosts should start with _port._proto"
+	}
+	if _, err := ParseTLSA(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateSSHFP(r Record) string {
+	if _, err := ParseSSHFP(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateNAPTR(r Record) string {
+	if _, err := ParseNAPTR(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateDS(r Record) string {
+	if _, err := ParseDS(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateURL(r Record) string {
+	u, err := url.Parse(r.Data)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Sprintf("%q is not a valid http or https URL", r.Data)
+	}
+	return ""
+}
+
+// MXData holds the data and priority of an MX record
+type MXData struct {
+	Priority uint16
+	Exchange string
+}
+
+// SRVData holds the data and priority of an SRV record as defined by
+// RFC 2782
+type SRVData struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// String returns the data as used in Record.Data, which does not include
+// the priority
+func (d SRVData) String() string {
+	return fmt.Sprintf("%d %d %s", d.Weight, d.Port, d.Target)
+}
+
+// ParseSRV parses the data and priority of an SRV record
+func ParseSRV(data string, priority int) (SRVData, error) {
+	var d SRVData
+	f, err := fields(data, 3)
+	if err != nil {
+		return d, fmt.Errorf("invalid SRV data %q: %s", data, err)
+	}
+	if priority < 0 || priority > 65535 {
+		return d, fmt.Errorf("invalid SRV priority %d", priority)
+	}
+
+	weight, err := strconv.ParseUint(f[0], 10, 16)
+	if err != nil {
+		return d, fmt.Errorf("invalid SRV weight %q", f[0])
+	}
+	port, err := strconv.ParseUint(f[1], 10, 16)
+	if err != nil {
+		return d, fmt.Errorf("invalid SRV port %q", f[1])
+	}
+
+	return SRVData{uint16(priority), uint16(weight), uint16(port), f[2]}, nil
+}
+
+// TXTData holds the character-strings of a TXT record
+type TXTData struct {
+	Strings []string
+}
+
+// Text returns the concatenation of all strings, which is how most
+// consumers like SPF and DKIM interpret TXT records
+func (d TXTData) Text() string {
+	return strings.Join(d.Strings, "")
+}
+
+// String returns the data as used in Record.Data. A single string is
+// returned as is, multiple strings are quoted and separated by spaces.
+func (d TXTData) String() string {
+	if len(d.Strings) == 1 && !strings.HasPrefix(d.Strings[0], `"`) {
+		return d.Strings[0]
+	}
+
+	quoted := make([]string, len(d.Strings))
+	for i, s := range d.Strings {
+		quoted[i] = quote(s)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ParseTXT parses the data of a TXT record. Data starting with a double
+// quote holds one or more quoted strings, any other data is a single
+// unquoted string.
+func ParseTXT(data string) (TXTData, error) {
+	if !strings.HasPrefix(data, `"`) {
+		return TXTData{[]string{data}}, nil
+	}
+
+	f, err := splitQuoted(data)
+	if err != nil {
+		return TXTData{}, fmt.Errorf("invalid TXT data %q: %s", data, err)
+	}
+	return TXTData{f}, nil
+}
+
+// SOAData holds the data of the SOA record of a zone.
+//
+// The EuroDNS API has no separate field for the minimum of the SOA record.
+// The TTL of the SOA record doubles as the negative caching TTL, so setting
+// Minimum changes the TTL of the record and vice versa. RFC 2308 caps the
+// negative caching TTL at the TTL of the SOA record anyway, so a single
+// value is used for both. The API does not expose the serial either, it is
+// maintained by EuroDNS.
+type SOAData struct {
+	// PrimaryNS is stored in the Data of the record
+	PrimaryNS  string
+	RespPerson string
+	Refresh    int
+	Retry      int
+	Expire     int
+	// Minimum is the negative caching TTL, which is the TTL of the record
+	Minimum int
+}
+
+// Parse returns a typed view of the record data, based on the record type:
+//
+//	A, AAAA                 net.IP
+//	CNAME, NS, PTR, ALIAS   string
+//	URL                     *url.URL
+//	MX                      MXData
+//	SRV                     SRVData
+//	TXT, SPF                TXTData
+//	SOA                     SOAData
+//	CAA                     CAAData
+//	TLSA                    TLSAData
+//	SSHFP                   SSHFPData
+//	NAPTR                   NAPTRData
+//	DS                      DSData
+func (r Record) Parse() (interface{}, error) {
+	switch r.Type {
+	case RecordTypeA, RecordTypeAAAA:
+		if reason := recordValidators[r.Type](r); reason != "" {
+			return nil, errors.New(reason)
+		}
+		return net.ParseIP(r.Data), nil
+	case RecordTypeCNAME, RecordTypeNS, RecordTypePTR, RecordTypeALIAS:
+		return r.Data, nil
+	case RecordTypeURL:
+		return url.Parse(r.Data)
+	case RecordTypeMX:
+		if r.Priority < 0 || r.Priority > 65535 {
+			return nil, fmt.Errorf("invalid MX priority %d", r.Priority)
+		}
+		return MXData{uint16(r.Priority), r.Data}, nil
+	case RecordTypeSRV:
+		return ParseSRV(r.Data, r.Priority)
+	case RecordTypeTXT, RecordTypeSPF:
+		return ParseTXT(r.Data)
+	case RecordTypeSOA:
+		return SOAData{r.Data, r.RespPerson, r.Refresh, r.Retry, r.Expire, r.TTL}, nil
+	case RecordTypeCAA:
+		return ParseCAA(r.Data)
+	case RecordTypeTLSA:
+		return ParseTLSA(r.Data)
+	case RecordTypeSSHFP:
+		return ParseSSHFP(r.Data)
+	case RecordTypeNAPTR:
+		return ParseNAPTR(r.Data)
+	case RecordTypeDS:
+		return ParseDS(r.Data)
+	}
+	return nil, fmt.Errorf("unsupported record type %s", r.Type)
+}
+
+// NewMXRecord returns an MX record for the host
+func NewMXRecord(host string, ttl int, priority uint16, exchange string) Record {
+	return Record{
+		Host:     host,
+		TTL:      ttl,
+		Type:     RecordTypeMX,
+		Priority: int(priority),
+		Data:     exchange,
+	}
+}
+
+// NewSRVRecord returns an SRV record for the host, which should be of the
+// form _service._proto
+func NewSRVRecord(host string, ttl int, priority, weight, port uint16, target string) Record {
+	return Record{
+		Host:     host,
+		TTL:      ttl,
+		Type:     RecordTypeSRV,
+		Priority: int(priority),
+		Data:     SRVData{priority, weight, port, target}.String(),
+	}
+}
+
+// NewTXTRecord returns a TXT record holding one or more strings
+func NewTXTRecord(host string, ttl int, texts ...string) Record {
+	return Record{
+		Host: host,
+		TTL:  ttl,
+		Type: RecordTypeTXT,
+		Data: TXTData{texts}.String(),
+	}
+}
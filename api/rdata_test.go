@@ -0,0 +1,175 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCAAData(t *testing.T) {
+	d, err := ParseCAA(`0 issue "letsencrypt.org"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d, CAAData{0, "issue", "letsencrypt.org"}) {
+		t.Errorf("unexpected CAA data %+v", d)
+	}
+	if s := d.String(); s != `0 issue "letsencrypt.org"` {
+		t.Errorf("unexpected CAA string %s", s)
+	}
+
+	if _, err := ParseCAA(`0 "issue"`); err == nil {
+		t.Error("expected CAA data without value to be rejected")
+	}
+	if _, err := ParseCAA(`0 issue "ab" "cd"`); err == nil {
+		t.Error("expected CAA data with several values to be rejected")
+	}
+}
+
+func TestTLSAData(t *testing.T) {
+	d, err := ParseTLSA("3 1 1 0C72AC70B745AC19998811B131D662C9 AC69DBDBE7CB23E5B514B56664C5D3D6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := TLSAData{3, 1, 1, "0C72AC70B745AC19998811B131D662C9AC69DBDBE7CB23E5B514B56664C5D3D6"}
+	if d != e {
+		t.Errorf("unexpected TLSA data %+v", d)
+	}
+
+	if _, err := ParseTLSA("3 1 1 xyz"); err == nil {
+		t.Error("expected non hexadecimal certificate data to be rejected")
+	}
+}
+
+func TestSSHFPData(t *testing.T) {
+	d, err := ParseSSHFP("4 2 123456789abcdef67890123456789abcdef67890123456789abcdef123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Algorithm != 4 || d.Type != 2 {
+		t.Errorf("unexpected SSHFP data %+v", d)
+	}
+
+	if _, err := ParseSSHFP("256 2 1234"); err == nil {
+		t.Error("expected out of range algorithm to be rejected")
+	}
+}
+
+func TestNAPTRData(t *testing.T) {
+	data := `100 10 "S" "SIP+D2U" "" _sip._udp.example.org.`
+	d, err := ParseNAPTR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NAPTRData{100, 10, "S", "SIP+D2U", "", "_sip._udp.example.org."}
+	if d != e {
+		t.Errorf("unexpected NAPTR data %+v", d)
+	}
+	if d.String() != data {
+		t.Errorf("unexpected NAPTR string %s", d.String())
+	}
+}
+
+func TestDSData(t *testing.T) {
+	d, err := ParseDS("60485 5 1 2BB183AF5F22588179A53B0A98631FAD1A292118")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.KeyTag != 60485 || d.Algorithm != 5 || d.DigestType != 1 {
+		t.Errorf("unexpected DS data %+v", d)
+	}
+
+	d, err = ParseDS("60485 5 1 2BB183AF5F22588179A5 3B0A98631FAD1A292118")
+	if err != nil || d.Digest != "2BB183AF5F22588179A53B0A98631FAD1A292118" {
+		t.Errorf("expected the split digest to be joined, got %+v: %v", d, err)
+	}
+}
+
+func TestValidateAdditionalTypes(t *testing.T) {
+	valid := []Record{
+		{Host: "@", Type: RecordTypeCAA, Data: `0 issue "letsencrypt.org"`},
+		{Host: "1.2", Type: RecordTypePTR, Data: "host.example.org."},
+		{Host: "_25._tcp.mail", Type: RecordTypeTLSA, Data: "3 1 1 0C72AC70"},
+		{Host: "@", Type: RecordTypeALIAS, Data: "lb.example.net."},
+		{Host: "www", Type: RecordTypeURL, Data: "https://example.org/"},
+	}
+	for _, r := range valid {
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid, got %s", r, err)
+		}
+	}
+
+	invalid := []Record{
+		{Host: "@", Type: RecordTypeCAA, Data: "issue letsencrypt.org"},
+		{Host: "mail", Type: RecordTypeTLSA, Data: "3 1 1 0C72AC70"},
+		{Host: "www", Type: RecordTypeURL, Data: "ftp://example.org/"},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", r)
+		}
+	}
+}
+
+func TestNewSRVRecord(t *testing.T) {
+	r := NewSRVRecord("_sip._tcp", 3600, 10, 60, 5060, "sip.example.org.")
+	if r.Priority != 10 || r.Data != "60 5060 sip.example.org." {
+		t.Errorf("unexpected SRV record %+v", r)
+	}
+
+	v, err := r.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := v.(SRVData); d != (SRVData{10, 60, 5060, "sip.example.org."}) {
+		t.Errorf("unexpected SRV data %+v", d)
+	}
+}
+
+func TestNewMXRecord(t *testing.T) {
+	v, err := NewMXRecord("@", 3600, 10, "mail.example.org.").Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := v.(MXData); d != (MXData{10, "mail.example.org."}) {
+		t.Errorf("unexpected MX data %+v", d)
+	}
+}
+
+func TestTXTData(t *testing.T) {
+	r := NewTXTRecord("@", 3600, "v=spf1 -all")
+	if r.Data != "v=spf1 -all" {
+		t.Errorf("expected single strings to be unquoted, got %s", r.Data)
+	}
+
+	r = NewTXTRecord("dkim._domainkey", 3600, "v=DKIM1; k=rsa; ", `p="MIIB"`)
+	if r.Data != `"v=DKIM1; k=rsa; " "p=\"MIIB\""` {
+		t.Errorf("expected multiple strings to be quoted, got %s", r.Data)
+	}
+
+	v, err := r.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := v.(TXTData)
+	if len(d.Strings) != 2 || d.Text() != `v=DKIM1; k=rsa; p="MIIB"` {
+		t.Errorf("unexpected TXT data %+v", d)
+	}
+
+	if _, err := ParseTXT(`"unterminated`); err == nil {
+		t.Error("expected unterminated strings to be rejected")
+	}
+}
+
+func TestRecordParse(t *testing.T) {
+	v, err := Record{Type: RecordTypeSOA, Data: "ns1.example.org.", TTL: 300, Refresh: 10800}.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := v.(SOAData); d.PrimaryNS != "ns1.example.org." || d.Minimum != 300 || d.Refresh != 10800 {
+		t.Errorf("unexpected SOA data %+v", d)
+	}
+
+	if _, err := (Record{Type: RecordTypeA, Data: "not an ip"}).Parse(); err == nil {
+		t.Error("expected invalid addresses to be rejected")
+	}
+}
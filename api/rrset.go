@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// PlanRRSet returns the minimal set of additions, changes and removals to
+// replace all records for the host with the given type in a zone as
+// returned by GetZoneInfo, without performing them. Records without a host
+// or type inherit those of the RRset. The records are validated against
+// the rest of the zone, unless the context was created by SkipValidation.
+func PlanRRSet(ctx context.Context, z Zone, host string, t RecordType, records []Record) (Plan, error) {
+	host = z.NormalizeHost(host)
+	live := z.FindAll(host, t)
+
+	desired := make([]*Record, len(records))
+	for i := range records {
+		r := records[i]
+		if r.Host == "" {
+			r.Host = host
+		}
+		if r.Type == "" {
+			r.Type = t
+		}
+		if z.NormalizeHost(r.Host) != host || r.Type != t {
+			return Plan{}, fmt.Errorf("%s record for host %q does not belong to the %s RRset of %q", r.Type, r.Host, t, host)
+		}
+		desired[i] = &r
+	}
+
+	if !ValidationSkipped(ctx) {
+		others := z.Filter(func(r *Record) bool {
+			return !(r.Type == t && z.NormalizeHost(r.Host) == host)
+		})
+		for _, r := range desired {
+			// the records replace the RRset, so their IDs are irrelevant
+			check := *r
+			check.ID = 0
+			if err := validateInZone(z.Name, others, check); err != nil {
+				return Plan{}, err
+			}
+			others = append(others, r)
+		}
+	}
+
+	return Diff(Zone{Name: z.Name, Records: live}, Zone{Name: z.Name, Records: desired}), nil
+}
+
+// ReplaceRRSet replaces all records for the host with the given type in a
+// zone as returned by GetZoneInfo, see PlanRRSet. The plan is submitted in
+// a single zone:update request, so the RRset never ends up in an
+// intermediate state.
+func ReplaceRRSet(ctx context.Context, c eurodnsgo.Client, z Zone, host string, t RecordType, records []Record) (Plan, error) {
+	p, err := PlanRRSet(ctx, z, host, t, records)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns api.ReplaceRRSet", zoneAttr(z.Name),
+		eurodnsgo.Attr{Key: "eurodns.host", Value: z.NormalizeHost(host)},
+		eurodnsgo.Attr{Key: "eurodns.type", Value: string(t)},
+	)
+	err = ApplyPlan(ctx, c, p)
+	eurodnsgo.EndSpan(span, err)
+
+	return p, err
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReplaceRRSet(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeA, TTL: 300, Data: "192.0.2.1"},
+			{ID: 2, Host: "www", Type: RecordTypeA, TTL: 300, Data: "192.0.2.2"},
+			{ID: 3, Host: "www", Type: RecordTypeA, TTL: 300, Data: "192.0.2.3"},
+			{ID: 4, Host: "www", Type: RecordTypeAAAA, TTL: 300, Data: "2001:db8::1"},
+		},
+	}
+	c := &testClient{}
+
+	p, err := ReplaceRRSet(context.TODO(), c, z, "www.example.org.", RecordTypeA, []Record{
+		{TTL: 300, Data: "192.0.2.2"},
+		{TTL: 300, Data: "198.51.100.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Records(Add)) != 0 || len(p.Records(Change)) != 1 || len(p.Records(Remove)) != 1 {
+		t.Errorf("expected a single change and removal, got %s", p)
+	}
+	for _, r := range p.Records(Remove) {
+		if r.Type != RecordTypeA {
+			t.Errorf("expected only A records to be removed, got %+v", r)
+		}
+	}
+	if len(c.requests) != 1 {
+		t.Errorf("expected a single request, got %d", len(c.requests))
+	}
+}
+
+func TestReplaceRRSetRejectsForeignRecords(t *testing.T) {
+	z := Zone{Name: "example.org"}
+
+	_, err := ReplaceRRSet(context.TODO(), &testClient{}, z, "www", RecordTypeA, []Record{
+		{Host: "ftp", Data: "192.0.2.1"},
+	})
+	if err == nil {
+		t.Error("expected records for another host to be rejected")
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"net"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// Query selects the records returned by SearchRecords. All criteria which
+// are set must match.
+type Query struct {
+	// Zones limits the search to the given zones instead of all zones
+	// returned by GetZoneList
+	Zones []string
+	// Types limits the search to records of the given types
+	Types []RecordType
+	// Host is a path.Match pattern like "www" or "*.dev" matched against
+	// the host relative to the zone, see Zone.NormalizeHost
+	Host string
+	// Data matches records of which the data contains the substring
+	Data string
+	// DataRegexp matches records of which the data matches the expression
+	DataRegexp *regexp.Regexp
+	// CIDR matches A and AAAA records with an address inside the network
+	CIDR *net.IPNet
+}
+
+// SearchResult is a record found by SearchRecords
+type SearchResult struct {
+	Zone   string
+	Record Record
+	// Err is set instead of the record when the zone could not be searched
+	Err error
+}
+
+// Match reports whether a record of the zone matches the query
+func (q Query) Match(z Zone, r *Record) bool {
+	if len(q.Types) > 0 {
+		found := false
+		for _, t := range q.Types {
+			found = found || t == r.Type
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Host != "" {
+		if ok, _ := path.Match(z.NormalizeHost(q.Host), z.NormalizeHost(r.Host)); !ok {
+			return false
+		}
+	}
+	if q.Data != "" && !strings.Contains(r.Data, q.Data) {
+		return false
+	}
+	if q.DataRegexp != nil && !q.DataRegexp.MatchString(r.Data) {
+		return false
+	}
+	if q.CIDR != nil {
+		if r.Type != RecordTypeA && r.Type != RecordTypeAAAA {
+			return false
+		}
+		if ip := net.ParseIP(r.Data); ip == nil || !q.CIDR.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// SearchRecords scans zones for records matching the query. Results are
+// streamed through the returned channel, which is closed once all zones
+// are searched or the context is cancelled. Zones which cannot be
+// retrieved are reported by a result with Err set.
+//
+// Every zone is fetched with GetZoneInfo, so repeated searches benefit
+// from a client which caches read requests.
+func SearchRecords(ctx context.Context, c eurodnsgo.Client, q Query) <-chan SearchResult {
+	ch := make(chan SearchResult)
+
+	go func() {
+		ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns api.SearchRecords")
+		defer span.End()
+		defer close(ch)
+
+		send := func(r SearchResult) bool {
+			select {
+			case ch <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		zones := q.Zones
+		if zones == nil {
+			var err error
+			if zones, err = GetZoneList(ctx, c); err != nil {
+				send(SearchResult{Err: err})
+				return
+			}
+		}
+
+		for _, name := range zones {
+			if ctx.Err() != nil {
+				return
+			}
+
+			z, err := GetZoneInfo(ctx, c, name)
+			if err != nil {
+				if !send(SearchResult{Zone: name, Err: err}) {
+					return
+				}
+				continue
+			}
+			if z.Name == "" {
+				z.Name = name
+			}
+
+			for _, r := range z.Records {
+				if q.Match(z, r) && !send(SearchResult{Zone: name, Record: *r}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"testing"
+)
+
+func TestQueryMatch(t *testing.T) {
+	_, network, _ := net.ParseCIDR("203.0.113.0/24")
+	z := Zone{Name: "example.org"}
+	www := &Record{Host: "www", Type: RecordTypeA, Data: "203.0.113.10"}
+	dev := &Record{Host: "api.dev", Type: RecordTypeA, Data: "198.51.100.1"}
+	mx := &Record{Host: "@", Type: RecordTypeMX, Data: "oldmail.example.net."}
+
+	tests := []struct {
+		q       Query
+		matches []*Record
+	}{
+		{Query{}, []*Record{www, dev, mx}},
+		{Query{CIDR: network}, []*Record{www}},
+		{Query{Types: []RecordType{RecordTypeMX, RecordTypeCNAME}}, []*Record{mx}},
+		{Query{Host: "*.dev"}, []*Record{dev}},
+		{Query{Host: "www.example.org."}, []*Record{www}},
+		{Query{Data: "oldmail"}, []*Record{mx}},
+		{Query{DataRegexp: regexp.MustCompile(`^203\.`)}, []*Record{www}},
+		{Query{Data: "203.0.113.10", Types: []RecordType{RecordTypeAAAA}}, nil},
+	}
+	for i, test := range tests {
+		var matches []*Record
+		for _, r := range []*Record{www, dev, mx} {
+			if test.q.Match(z, r) {
+				matches = append(matches, r)
+			}
+		}
+		if len(matches) != len(test.matches) {
+			t.Errorf("query %d: expected %d matches, got %d", i, len(test.matches), len(matches))
+			continue
+		}
+		for j := range matches {
+			if matches[j] != test.matches[j] {
+				t.Errorf("query %d: unexpected match %+v", i, matches[j])
+			}
+		}
+	}
+}
+
+func TestSearchRecordsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the test client returns empty zones, so nothing but the closing of
+	// the channel is expected
+	for r := range SearchRecords(ctx, &testClient{}, Query{Zones: []string{"example.org"}}) {
+		t.Errorf("unexpected result %+v", r)
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// DomainService provides the domains of an account
+type DomainService interface {
+	// List returns the names of all manageable domains, see GetDomainList
+	List(ctx context.Context) ([]string, error)
+}
+
+// ZoneService provides the zones of an account
+type ZoneService interface {
+	// List returns the names of all zones, see GetZoneList
+	List(ctx context.Context) ([]string, error)
+	// Info returns a zone with its records, see GetZoneInfo
+	Info(ctx context.Context, name string) (Zone, error)
+	// Plan returns the changes needed to reach the desired zone, see PlanZone
+	Plan(ctx context.Context, desired Zone) (Plan, error)
+	// Apply executes a Plan, see ApplyPlan
+	Apply(ctx context.Context, p Plan) error
+	// SOA returns the SOA data of a zone, see GetSOA
+	SOA(ctx context.Context, name string) (SOAData, error)
+	// UpdateSOA changes the SOA record of a zone, see UpdateSOA
+	UpdateSOA(ctx context.Context, z Zone, u SOAUpdate) error
+}
+
+// RecordService provides the records of the zones of an account
+type RecordService interface {
+	// Info returns a record by ID, see GetRecordInfo
+	Info(ctx context.Context, id int) (Record, error)
+	// Add adds a record to a zone, see ZoneRecordAdd
+	Add(ctx context.Context, z Zone, r Record) error
+	// Change changes a record of a zone, see ZoneRecordChange
+	Change(ctx context.Context, z Zone, r Record) error
+	// Delete removes a record from a zone, see ZoneRecordDelete
+	Delete(ctx context.Context, z Zone, r Record) error
+	// Upsert adds or changes a record, see ZoneRecordUpsert
+	Upsert(ctx context.Context, z Zone, r Record, match Matcher) (MutationType, error)
+	// ReplaceRRSet replaces all records of an RRset, see ReplaceRRSet
+	ReplaceRRSet(ctx context.Context, z Zone, host string, t RecordType, records []Record) (Plan, error)
+}
+
+// Services bundles the services of an account, so applications can depend
+// on these narrow interfaces instead of a eurodnsgo.Client. The apitest
+// package provides an in-memory implementation for tests.
+type Services struct {
+	Domains DomainService
+	Zones   ZoneService
+	Records RecordService
+}
+
+// NewServices returns the services performing requests through the client
+func NewServices(c eurodnsgo.Client) Services {
+	return Services{
+		Domains: NewDomainService(c),
+		Zones:   NewZoneService(c),
+		Records: NewRecordService(c),
+	}
+}
+
+// NewDomainService returns a DomainService performing requests through the
+// client
+func NewDomainService(c eurodnsgo.Client) DomainService {
+	return domainService{c}
+}
+
+// NewZoneService returns a ZoneService performing requests through the
+// client
+func NewZoneService(c eurodnsgo.Client) ZoneService {
+	return zoneService{c}
+}
+
+// NewRecordService returns a RecordService performing requests through
+// the client
+func NewRecordService(c eurodnsgo.Client) RecordService {
+	return recordService{c}
+}
+
+type domainService struct {
+	c eurodnsgo.Client
+}
+
+func (s domainService) List(ctx context.Context) ([]string, error) {
+	return GetDomainList(ctx, s.c)
+}
+
+type zoneService struct {
+	c eurodnsgo.Client
+}
+
+func (s zoneService) List(ctx context.Context) ([]string, error) {
+	return GetZoneList(ctx, s.c)
+}
+
+func (s zoneService) Info(ctx context.Context, name string) (Zone, error) {
+	return GetZoneInfo(ctx, s.c, name)
+}
+
+func (s zoneService) Plan(ctx context.Context, desired Zone) (Plan, error) {
+	return PlanZone(ctx, s.c, desired)
+}
+
+func (s zoneService) Apply(ctx context.Context, p Plan) error {
+	return ApplyPlan(ctx, s.c, p)
+}
+
+func (s zoneService) SOA(ctx context.Context, name string) (SOAData, error) {
+	return GetSOA(ctx, s.c, name)
+}
+
+func (s zoneService) UpdateSOA(ctx context.Context, z Zone, u SOAUpdate) error {
+	return UpdateSOA(ctx, s.c, z, u)
+}
+
+type recordService struct {
+	c eurodnsgo.Client
+}
+
+func (s recordService) Info(ctx context.Context, id int) (Record, error) {
+	return GetRecordInfo(ctx, s.c, id)
+}
+
+func (s recordService) Add(ctx context.Context, z Zone, r Record) error {
+	return ZoneRecordAdd(ctx, s.c, z, r)
+}
+
+func (s recordService) Change(ctx context.Context, z Zone, r Record) error {
+	return ZoneRecordChange(ctx, s.c, z, r)
+}
+
+func (s recordService) Delete(ctx context.Context, z Zone, r Record) error {
+	return ZoneRecordDelete(ctx, s.c, z, r)
+}
+
+func (s recordService) Upsert(ctx context.Context, z Zone, r Record, match Matcher) (MutationType, error) {
+	return ZoneRecordUpsert(ctx, s.c, z, r, match)
+}
+
+func (s recordService) ReplaceRRSet(ctx context.Context, z Zone, host string, t RecordType, records []Record) (Plan, error) {
+	return ReplaceRRSet(ctx, s.c, z, host, t, records)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestServices(t *testing.T) {
+	c := &testClient{}
+	s := NewServices(c)
+	ctx := SkipValidation(context.TODO())
+	z := Zone{Name: "example.org", Records: []*Record{{ID: 1, Host: "www", Type: RecordTypeA, Data: "192.0.2.1"}}}
+	r := Record{Host: "mail", Type: RecordTypeA, Data: "192.0.2.2"}
+
+	if _, err := s.Domains.List(ctx); err != nil {
+		t.Error(err)
+	}
+	if _, err := s.Zones.List(ctx); err != nil {
+		t.Error(err)
+	}
+	if _, err := s.Zones.Info(ctx, "example.org"); err != nil {
+		t.Error(err)
+	}
+	if _, err := s.Records.Info(ctx, 1); err != nil {
+		t.Error(err)
+	}
+	if err := s.Records.Add(ctx, z, r); err != nil {
+		t.Error(err)
+	}
+	if err := s.Records.Change(ctx, z, *z.Records[0]); err != nil {
+		t.Error(err)
+	}
+	if err := s.Records.Delete(ctx, z, *z.Records[0]); err != nil {
+		t.Error(err)
+	}
+	if m, err := s.Records.Upsert(ctx, z, r, nil); err != nil || m != Add {
+		t.Errorf("expected the record to be added, got %s, %v", m, err)
+	}
+	if _, err := s.Records.ReplaceRRSet(ctx, z, "www", RecordTypeA, []Record{{Data: "192.0.2.3"}}); err != nil {
+		t.Error(err)
+	}
+
+	expected := []string{
+		"domain:list", "zone:list", "zone:info", "record:info",
+		"zone:update", "zone:update", "zone:update", "zone:update", "zone:update",
+	}
+	if len(c.requests) != len(expected) {
+		t.Fatalf("expected %d requests, got %d", len(expected), len(c.requests))
+	}
+	for i, sr := range c.requests {
+		if op := sr.Namespace + ":" + sr.Method; op != expected[i] {
+			t.Errorf("request %d: expected %s, got %s", i, expected[i], op)
+		}
+	}
+}
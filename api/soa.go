@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// ErrNoSOA is returned when a zone does not contain an SOA record
+var ErrNoSOA = errors.New("zone has no SOA record")
+
+// SOAUpdate holds the SOA properties to change, nil fields are left as is
+type SOAUpdate struct {
+	RespPerson *string
+	Refresh    *int
+	Retry      *int
+	Expire     *int
+	// Minimum sets the negative caching TTL, which is also the TTL of the
+	// SOA record, see SOAData
+	Minimum *int
+}
+
+func (u SOAUpdate) empty() bool {
+	return u.RespPerson == nil && u.Refresh == nil && u.Retry == nil && u.Expire == nil && u.Minimum == nil
+}
+
+// apply returns a copy of the SOA data with the update applied
+func (u SOAUpdate) apply(d SOAData) SOAData {
+	if u.RespPerson != nil {
+		d.RespPerson = *u.RespPerson
+	}
+	if u.Refresh != nil {
+		d.Refresh = *u.Refresh
+	}
+	if u.Retry != nil {
+		d.Retry = *u.Retry
+	}
+	if u.Expire != nil {
+		d.Expire = *u.Expire
+	}
+	if u.Minimum != nil {
+		d.Minimum = *u.Minimum
+	}
+	return d
+}
+
+// Validate checks the SOA timers against the ranges recommended by
+// RFC 1912, and the negative caching TTL against RFC 2308
+func (d SOAData) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		r := Record{Host: "@", Type: RecordTypeSOA}
+		return &ValidationError{r, fmt.Sprintf(format, args...)}
+	}
+
+	switch {
+	case d.Refresh < 1200 || d.Refresh > 43200:
+		return invalid("refresh %d should be between 1200 and 43200 seconds", d.Refresh)
+	case d.Retry < 180 || d.Retry >= d.Refresh:
+		return invalid("retry %d should be at least 180 seconds and less than refresh", d.Retry)
+	case d.Expire < 1209600 || d.Expire > 2419200:
+		return invalid("expire %d should be between 1209600 and 2419200 seconds", d.Expire)
+	case d.Minimum < 300 || d.Minimum > 86400:
+		return invalid("minimum %d should be between 300 and 86400 seconds", d.Minimum)
+	case d.RespPerson != "" && !validDomainName(d.RespPerson):
+		return invalid("%q is not a valid mailbox domain name", d.RespPerson)
+	}
+	return nil
+}
+
+func soaRecord(z Zone) (*Record, error) {
+	for _, r := range z.Filter(anyRecord) {
+		if r.Type == RecordTypeSOA {
+			return r, nil
+		}
+	}
+	return nil, ErrNoSOA
+}
+
+// GetSOA returns the SOA data of a zone
+func GetSOA(ctx context.Context, c eurodnsgo.Client, domain string) (SOAData, error) {
+	z, err := GetZoneInfo(ctx, c, domain)
+	if err != nil {
+		return SOAData{}, err
+	}
+
+	r, err := soaRecord(z)
+	if err != nil {
+		return SOAData{}, err
+	}
+
+	v, err := r.Parse()
+	if err != nil {
+		return SOAData{}, err
+	}
+	return v.(SOAData), nil
+}
+
+func updateSOARequest(v interface{}, z Zone, id int, u SOAUpdate) *eurodnsgo.SoapRequest {
+	sr := eurodnsgo.NewSoapRequest("zone", "update", &v)
+
+	// only the updated fields are sent along
+	var buf bytes.Buffer
+	if u.Expire != nil {
+		fmt.Fprintf(&buf, "<record:expire>%d</record:expire>", *u.Expire)
+	}
+	if u.Refresh != nil {
+		fmt.Fprintf(&buf, "<record:refresh>%d</record:refresh>", *u.Refresh)
+	}
+	if u.RespPerson != nil {
+		buf.WriteString("<record:resp_person>")
+		xml.EscapeText(&buf, []byte(*u.RespPerson))
+		buf.WriteString("</record:resp_person>")
+	}
+	if u.Retry != nil {
+		fmt.Fprintf(&buf, "<record:retry>%d</record:retry>", *u.Retry)
+	}
+	if u.Minimum != nil {
+		fmt.Fprintf(&buf, "<record:ttl>%d</record:ttl>", *u.Minimum)
+	}
+
+	zoneRecord := eurodnsgo.NewParam("zone", "record", buf.Bytes(), eurodnsgo.Attr{Key: "id", Value: id})
+	zoneChange := eurodnsgo.NewParam("zone", "change", zoneRecord)
+	zoneRecords := eurodnsgo.NewParam("zone", "records", zoneChange)
+	zoneName := eurodnsgo.NewParam("zone", "name", z.Name)
+
+	sr.AddParam(zoneName)
+	sr.AddParam(zoneRecords)
+	return sr
+}
+
+// PlanSOA returns the change of the SOA record of a zone as returned by
+// GetZoneInfo needed to apply the update, without performing it. The
+// resulting SOA is validated first, unless the context was created by
+// SkipValidation.
+func PlanSOA(ctx context.Context, z Zone, u SOAUpdate) (Plan, error) {
+	p := Plan{Zone: z.Name}
+	if u.empty() {
+		return p, nil
+	}
+
+	r, err := soaRecord(z)
+	if err != nil {
+		return p, err
+	}
+	current, err := r.Parse()
+	if err != nil {
+		return p, err
+	}
+
+	d := u.apply(current.(SOAData))
+	if !ValidationSkipped(ctx) {
+		if err := d.Validate(); err != nil {
+			return p, err
+		}
+	}
+
+	updated := *r
+	updated.RespPerson = d.RespPerson
+	updated.Refresh = d.Refresh
+	updated.Retry = d.Retry
+	updated.Expire = d.Expire
+	updated.TTL = d.Minimum
+	if !recordEqual(z.Name, r, &updated) {
+		p.Actions = append(p.Actions, PlanAction{Type: Change, Record: updated, Current: r})
+	}
+	return p, nil
+}
+
+// UpdateSOA changes the given properties of the SOA record of a zone as
+// returned by GetZoneInfo, leaving all other properties untouched. The
+// resulting SOA is validated first, unless the context was created by
+// SkipValidation.
+func UpdateSOA(ctx context.Context, c eurodnsgo.Client, z Zone, u SOAUpdate) error {
+	var v interface{}
+
+	p, err := PlanSOA(ctx, z, u)
+	if err != nil || p.Empty() {
+		return err
+	}
+
+	return schedule(ctx, c, updateSOARequest(v, z, p.Actions[0].Record.ID, u))
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpdateSOARequest(t *testing.T) {
+	e := `<?xml version="1.0" encoding="UTF-8"?>
+<request xmlns:zone="http://www.eurodns.com/zone">
+	<zone:update><zone:name>zone</zone:name><zone:records><zone:change><zone:record id="1234"><record:refresh>7200</record:refresh><record:ttl>900</record:ttl></zone:record></zone:change></zone:records></zone:update>
+</request>`
+	refresh, minimum := 7200, 900
+	var v interface{}
+	sr := updateSOARequest(v, Zone{Name: "zone"}, 1234, SOAUpdate{Refresh: &refresh, Minimum: &minimum})
+
+	testParams(t, sr, e)
+}
+
+func TestSOAValidate(t *testing.T) {
+	d := SOAData{
+		PrimaryNS:  "ns1.example.org.",
+		RespPerson: "hostmaster.example.org.",
+		Refresh:    10800,
+		Retry:      3600,
+		Expire:     1209600,
+		Minimum:    3600,
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	retry := 20000
+	if err := (SOAUpdate{Retry: &retry}).apply(d).Validate(); err == nil {
+		t.Error("expected retry exceeding refresh to be rejected")
+	}
+
+	minimum := 30
+	if err := (SOAUpdate{Minimum: &minimum}).apply(d).Validate(); err == nil {
+		t.Error("expected a negative caching TTL of 30 seconds to be rejected")
+	}
+}
+
+func TestUpdateSOAWithoutSOA(t *testing.T) {
+	refresh := 7200
+	err := UpdateSOA(context.TODO(), nil, Zone{Name: "zone"}, SOAUpdate{Refresh: &refresh})
+	if err != ErrNoSOA {
+		t.Errorf("expected ErrNoSOA, got %v", err)
+	}
+}
+
+func TestPlanSOA(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "@", Type: RecordTypeSOA, TTL: 3600, Data: "ns1.example.org.", RespPerson: "hostmaster.example.org.", Refresh: 10800, Retry: 3600, Expire: 1209600},
+		},
+	}
+
+	refresh, minimum := 10800, 900
+	p, err := PlanSOA(context.TODO(), z, SOAUpdate{Refresh: &refresh})
+	if err != nil || !p.Empty() {
+		t.Errorf("expected an empty plan for unchanged values, got %v, %v", p, err)
+	}
+
+	p, err = PlanSOA(context.TODO(), z, SOAUpdate{Minimum: &minimum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes := p.Records(Change); len(changes) != 1 || changes[0].ID != 1 || changes[0].TTL != 900 || changes[0].Refresh != 10800 {
+		t.Errorf("expected the minimum to change the TTL of the SOA record, got %+v", p)
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// Matcher reports whether an existing record of the zone is the record
+// to be replaced by the desired record
+type Matcher func(z Zone, existing, desired *Record) bool
+
+// MatchHostType matches records with the same host and type
+func MatchHostType(z Zone, existing, desired *Record) bool {
+	return sameRRSet(z.Name, existing, desired)
+}
+
+// MatchHostTypeData matches records with the same host, type and data
+func MatchHostTypeData(z Zone, existing, desired *Record) bool {
+	return sameData(z.Name, existing, desired)
+}
+
+// PlanUpsert returns the action needed to make sure the record exists in
+// the zone, without performing it. When an existing record is found by the
+// Matcher it is changed, reusing its ID, otherwise the record is added. A
+// nil Matcher defaults to MatchHostType. The returned MutationType is the
+// type of the action, or Unchanged for an empty plan. Multiple matching
+// records are reported as an error.
+func PlanUpsert(z Zone, r Record, match Matcher) (Plan, MutationType, error) {
+	if match == nil {
+		match = MatchHostType
+	}
+
+	p := Plan{Zone: z.Name}
+	found := z.Filter(func(e *Record) bool {
+		return match(z, e, &r)
+	})
+	switch len(found) {
+	case 0:
+		r.ID = 0
+		p.Actions = append(p.Actions, PlanAction{Type: Add, Record: r})
+		return p, Add, nil
+	case 1:
+		r.ID = found[0].ID
+		if recordEqual(z.Name, found[0], &r) {
+			return p, Unchanged, nil
+		}
+		p.Actions = append(p.Actions, PlanAction{Type: Change, Record: r, Current: found[0]})
+		return p, Change, nil
+	}
+	return p, "", fmt.Errorf("%d records match %s record for host %q", len(found), r.Type, r.Host)
+}
+
+// ZoneRecordUpsert makes sure the record exists in a zone as returned by
+// GetZoneInfo, see PlanUpsert. The record is added by ZoneRecordAdd or
+// changed by ZoneRecordChange. The returned MutationType reports whether
+// the record was added, changed or already Unchanged.
+func ZoneRecordUpsert(ctx context.Context, c eurodnsgo.Client, z Zone, r Record, match Matcher) (MutationType, error) {
+	p, t, err := PlanUpsert(z, r, match)
+	if err != nil {
+		return "", err
+	}
+
+	switch t {
+	case Add:
+		return t, ZoneRecordAdd(ctx, c, z, p.Actions[0].Record)
+	case Change:
+		return t, ZoneRecordChange(ctx, c, z, p.Actions[0].Record)
+	}
+	return t, nil
+}
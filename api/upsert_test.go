@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestZoneRecordUpsert(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+			{ID: 2, Host: "mail", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.2"},
+			{ID: 3, Host: "mail", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.3"},
+		},
+	}
+	ctx := context.TODO()
+	c := &testClient{}
+
+	m, err := ZoneRecordUpsert(ctx, c, z, Record{Host: "www.example.org.", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"}, nil)
+	if err != nil || m != Unchanged || len(c.requests) != 0 {
+		t.Errorf("expected an identical record to be left alone, got %s %v", m, err)
+	}
+
+	m, err = ZoneRecordUpsert(ctx, c, z, Record{Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.9"}, nil)
+	if err != nil || m != Change {
+		t.Fatalf("expected the record to be changed, got %s %v", m, err)
+	}
+	if content := c.requests[0].PrepareContent(); !strings.Contains(content, `<zone:change><zone:record id="1">`) {
+		t.Errorf("expected record 1 to be changed, got %s", content)
+	}
+
+	m, err = ZoneRecordUpsert(ctx, c, z, Record{Host: "ftp", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"}, nil)
+	if err != nil || m != Add {
+		t.Errorf("expected the record to be added, got %s %v", m, err)
+	}
+
+	if _, err = ZoneRecordUpsert(ctx, c, z, Record{Host: "mail", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.4"}, nil); err == nil {
+		t.Error("expected multiple matches to be rejected")
+	}
+
+	m, err = ZoneRecordUpsert(ctx, c, z, Record{Host: "mail", Type: RecordTypeA, TTL: 300, Data: "192.0.2.3"}, MatchHostTypeData)
+	if err != nil || m != Change {
+		t.Errorf("expected the TTL of record 3 to be changed, got %s %v", m, err)
+	}
+}
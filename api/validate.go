@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// MinTTL is the lowest TTL accepted by validation. A TTL of 0 is
+	// accepted as well and leaves the TTL to the EuroDNS default.
+	MinTTL = 60
+	// MaxTTL is the highest TTL accepted by validation
+	MaxTTL = 604800
+)
+
+type skipValidationKey struct{}
+
+// SkipValidation returns a copy of the context which disables the local
+// validation performed by ZoneRecordAdd, ZoneRecordChange and PlanZone
+func SkipValidation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipValidationKey{}, true)
+}
+
+// ValidationSkipped reports whether the context was created by
+// SkipValidation
+func ValidationSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipValidationKey{}).(bool)
+	return skip
+}
+
+// ValidationError describes why a record was rejected by validation
+type ValidationError struct {
+	Record Record
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s record for host %q: %s", e.Record.Type, e.Record.Host, e.Reason)
+}
+
+// ValidationErrors holds all problems found while validating a zone
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// recordValidators holds the type specific validation of record data
+var recordValidators = map[RecordType]func(r Record) string{
+	RecordTypeA:     validateA,
+	RecordTypeAAAA:  validateAAAA,
+	RecordTypeCNAME: validateCNAME,
+	RecordTypeMX:    validateMX,
+	RecordTypeNS:    validateTarget,
+	RecordTypeTXT:   validateTXT,
+	RecordTypeSPF:   validateTXT,
+	RecordTypeSRV:   validateSRV,
+	RecordTypeSOA:   func(Record) string { return "" },
+	RecordTypeCAA:   validateCAA,
+	RecordTypePTR:   validateTarget,
+	RecordTypeTLSA:  validateTLSA,
+	RecordTypeSSHFP: validateSSHFP,
+	RecordTypeNAPTR: validateNAPTR,
+	RecordTypeDS:    validateDS,
+	RecordTypeALIAS: validateTarget,
+	RecordTypeURL:   validateURL,
+}
+
+// Validate checks the host, TTL and type specific data of the record
+func (r Record) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return &ValidationError{r, fmt.Sprintf(format, args...)}
+	}
+
+	validator, ok := recordValidators[r.Type]
+	if !ok {
+		return invalid("unsupported record type")
+	}
+	if !validHost(r.Host) {
+		return invalid("invalid host name")
+	}
+	if r.TTL != 0 && (r.TTL < MinTTL || r.TTL > MaxTTL) {
+		return invalid("TTL %d is outside of the range %d-%d", r.TTL, MinTTL, MaxTTL)
+	}
+	if reason := validator(r); reason != "" {
+		return invalid(reason)
+	}
+	return nil
+}
+
+// Validate checks all records of the zone, and verifies that no records
+// are duplicated and that CNAME records do not share their host with any
+// other record
+func (z Zone) Validate() error {
+	var errs ValidationErrors
+	for i, r := range z.Records {
+		if r == nil {
+			errs = append(errs, &ValidationError{Record{}, fmt.Sprintf("record %d is nil", i)})
+			continue
+		}
+		if err := validateInZone(z.Name, z.Records[:i], *r); err != nil {
+			errs = append(errs, err.(*ValidationError))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateRecord validates a record to be added to or changed in the zone,
+// checking it for conflicts with the records of the zone like
+// ZoneRecordAdd and ZoneRecordChange do
+func (z Zone) ValidateRecord(r Record) error {
+	return validateInZone(z.Name, z.Records, r)
+}
+
+// validateInZone validates the record and checks it for conflicts with the
+// other records of a zone. Records with the same ID are not considered
+// conflicting, as they are replaced by the record.
+func validateInZone(zone string, others []*Record, r Record) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.Type == RecordTypeCNAME && normalizeHost(zone, r.Host) == "@" {
+		return &ValidationError{r, errCNAMEApex}
+	}
+
+	for _, o := range others {
+		if o == nil {
+			continue
+		}
+		if (r.ID != 0 && o.ID == r.ID) || normalizeHost(zone, o.Host) != normalizeHost(zone, r.Host) {
+			continue
+		}
+		if sameData(zone, o, &r) {
+			return &ValidationError{r, "duplicate record"}
+		}
+		if r.Type == RecordTypeCNAME || o.Type == RecordTypeCNAME {
+			return &ValidationError{r, fmt.Sprintf("CNAME records cannot coexist with other records, found %s", o.Type)}
+		}
+	}
+	return nil
+}
+
+func validHost(h string) bool {
+	if h == "" || h == "@" {
+		return true
+	}
+	h = strings.TrimSuffix(h, ".")
+	if len(h) > 253 {
+		return false
+	}
+
+	for i, l := range strings.Split(h, ".") {
+		if i == 0 && l == "*" {
+			continue
+		}
+		if !validLabel(l) {
+			return false
+		}
+	}
+	return true
+}
+
+func validLabel(l string) bool {
+	if len(l) == 0 || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+		return false
+	}
+	for _, c := range l {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validDomainName checks a domain name used inside record data
+func validDomainName(n string) bool {
+	return n != "" && n != "@" && n != "." && validHost(n)
+}
+
+func validateA(r Record) string {
+	if ip := net.ParseIP(r.Data); ip == nil || ip.To4() == nil || strings.Contains(r.Data, ":") {
+		return fmt.Sprintf("%q is not an IPv4 address", r.Data)
+	}
+	return ""
+}
+
+func validateAAAA(r Record) string {
+	if ip := net.ParseIP(r.Data); ip == nil || !strings.Contains(r.Data, ":") {
+		return fmt.Sprintf("%q is not an IPv6 address", r.Data)
+	}
+	return ""
+}
+
+// errCNAMEApex is the reason CNAME records at the zone apex are rejected
+const errCNAMEApex = "CNAME records are not allowed at the zone apex"
+
+// validateCNAME only recognizes the apex as "@" or an empty host, the zone
+// name in any other form is checked by validateInZone
+func validateCNAME(r Record) string {
+	if normalizeHost("", r.Host) == "@" {
+		return errCNAMEApex
+	}
+	if !validDomainName(r.Data) {
+		return fmt.Sprintf("%q is not a valid domain name", r.Data)
+	}
+	return ""
+}
+
+func validateMX(r Record) string {
+	if r.Data == "." {
+		// null MX as defined by RFC 7505
+		if r.Priority != 0 {
+			return "null MX records require priority 0"
+		}
+		return ""
+	}
+	if r.Priority < 0 || r.Priority > 65535 {
+		return "MX priority should be between 0 and 65535"
+	}
+	if !validDomainName(r.Data) {
+		return fmt.Sprintf("%q is not a valid domain name", r.Data)
+	}
+	return ""
+}
+
+// validateTarget validates records which only hold a domain name
+func validateTarget(r Record) string {
+	if !validDomainName(r.Data) {
+		return fmt.Sprintf("%q is not a valid domain name", r.Data)
+	}
+	return ""
+}
+
+func validateTXT(r Record) string {
+	if r.Data == "" {
+		return fmt.Sprintf("%s records require data", r.Type)
+	}
+	if _, err := ParseTXT(r.Data); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+func validateSRV(r Record) string {
+	labels := strings.Split(normalizeHost("", r.Host), ".")
+	if len(labels) < 2 || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+		return "SRV hosts should start with _service._proto"
+	}
+	if r.Priority < 0 || r.Priority > 65535 {
+		return "SRV priority should be between 0 and 65535"
+	}
+	d, err := ParseSRV(r.Data, r.Priority)
+	if err != nil {
+		return fmt.Sprintf("%q does not match the format \"weight port target\"", r.Data)
+	}
+	if d.Target != "." && !validDomainName(d.Target) {
+		return fmt.Sprintf("%q is not a valid domain name", d.Target)
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRecordValidate(t *testing.T) {
+	valid := []Record{
+		{Host: "www", Type: RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+		{Host: "@", Type: RecordTypeAAAA, Data: "2001:db8::1"},
+		{Host: "*.dev", Type: RecordTypeCNAME, Data: "www.example.org."},
+		{Host: "@", Type: RecordTypeMX, Priority: 10, Data: "mail.example.org."},
+		{Host: "@", Type: RecordTypeMX, Data: "."},
+		{Host: "@", Type: RecordTypeMX, Data: "example-org.mail.protection.outlook.com."},
+		{Host: "@", Type: RecordTypeSPF, Data: "v=spf1 mx -all"},
+		{Host: "_dmarc", Type: RecordTypeTXT, Data: "v=DMARC1; p=none"},
+		{Host: "_sip._tcp", Type: RecordTypeSRV, Priority: 10, Data: "60 5060 sip.example.org."},
+	}
+	for _, r := range valid {
+		if err := r.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid, got %s", r, err)
+		}
+	}
+
+	invalid := []Record{
+		{Host: "www", Type: RecordTypeA, Data: "192.0.2.256"},
+		{Host: "www", Type: RecordTypeA, Data: "2001:db8::1"},
+		{Host: "www", Type: RecordTypeAAAA, Data: "192.0.2.1"},
+		{Host: "www", Type: RecordTypeA, TTL: 1, Data: "192.0.2.1"},
+		{Host: "-www", Type: RecordTypeA, Data: "192.0.2.1"},
+		{Host: "w w w", Type: RecordTypeA, Data: "192.0.2.1"},
+		{Host: "@", Type: RecordTypeCNAME, Data: "www.example.org."},
+		{Host: "@", Type: RecordTypeMX, Priority: 65536, Data: "mail.example.org."},
+		{Host: "@", Type: RecordTypeMX, Priority: -1, Data: "mail.example.org."},
+		{Host: "@", Type: RecordTypeTXT},
+		{Host: "@", Type: RecordTypeSPF},
+		{Host: "sip", Type: RecordTypeSRV, Data: "60 5060 sip.example.org."},
+		{Host: "_sip._tcp", Type: RecordTypeSRV, Data: "5060 sip.example.org."},
+		{Host: "www", Type: RecordType("BOGUS"), Data: "x"},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", r)
+		}
+	}
+}
+
+func TestZoneValidate(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeA, Data: "192.0.2.1"},
+			{ID: 2, Host: "www", Type: RecordTypeA, Data: "192.0.2.2"},
+			{ID: 3, Host: "ftp", Type: RecordTypeCNAME, Data: "www.example.org."},
+		},
+	}
+	if err := z.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	z.Records = append(z.Records,
+		&Record{Host: "www.example.org.", Type: RecordTypeA, Data: "192.0.2.1"},
+		&Record{Host: "ftp", Type: RecordTypeTXT, Data: "text"},
+	)
+	err := z.Validate()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 2 {
+		t.Fatalf("expected a duplicate and a CNAME conflict, got %v", err)
+	}
+
+	z.Records = append(z.Records[:3], nil)
+	err = z.Validate()
+	if errs, ok := err.(ValidationErrors); !ok || len(errs) != 1 {
+		t.Fatalf("expected an error for the nil record, got %v", err)
+	}
+}
+
+func TestZoneRecordAddValidation(t *testing.T) {
+	z := Zone{
+		Name: "example.org",
+		Records: []*Record{
+			{ID: 1, Host: "www", Type: RecordTypeCNAME, Data: "example.org."},
+		},
+	}
+	r := Record{Host: "www", Type: RecordTypeA, Data: "192.0.2.1"}
+
+	// the client is never reached when validation fails
+	if err := ZoneRecordAdd(context.TODO(), nil, z, r); err == nil {
+		t.Fatal("expected the CNAME conflict to be detected")
+	}
+
+	// changing the CNAME itself does not conflict
+	r.ID = 1
+	if err := validateInZone(z.Name, z.Records, r); err != nil {
+		t.Fatal(err)
+	}
+
+	// the apex is recognized in every form
+	for _, host := range []string{"@", "example.org", "example.org."} {
+		apex := Record{Host: host, Type: RecordTypeCNAME, Data: "www.example.net."}
+		if err := validateInZone(z.Name, nil, apex); err == nil {
+			t.Errorf("expected a CNAME at apex %q to be rejected", host)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import "strings"
+
+// normalizeHost returns the host relative to the zone in lowercase. The
+// apex is returned as "@", hosts outside of the zone are returned fully
+// qualified with a trailing dot. Without a zone name only the apex and
+// the case are normalized.
+func normalizeHost(zone, host string) string {
+	h := strings.ToLower(host)
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	if h == "" || h == "@" {
+		return "@"
+	}
+
+	fqdn := strings.HasSuffix(h, ".")
+	h = strings.TrimSuffix(h, ".")
+	switch {
+	case zone == "":
+	case h == zone:
+		return "@"
+	case strings.HasSuffix(h, "."+zone):
+		return strings.TrimSuffix(h, "."+zone)
+	case fqdn:
+		return h + "."
+	}
+	return h
+}
+
+// NormalizeHost returns the host relative to the zone, treating "@", the
+// bare zone name and the fully qualified zone name as the apex "@". Hosts
+// are compared case-insensitively and returned in lowercase.
+func (z Zone) NormalizeHost(host string) string {
+	return normalizeHost(z.Name, host)
+}
+
+// Find returns the first record for the host with the given type, or nil
+// when there is none
+func (z Zone) Find(host string, t RecordType) *Record {
+	if res := z.FindAll(host, t); len(res) > 0 {
+		return res[0]
+	}
+	return nil
+}
+
+// FindAll returns all records for the host with the given type
+func (z Zone) FindAll(host string, t RecordType) []*Record {
+	host = z.NormalizeHost(host)
+	return z.Filter(func(r *Record) bool {
+		return r.Type == t && z.NormalizeHost(r.Host) == host
+	})
+}
+
+// Filter returns all records for which the function returns true, nil
+// records are skipped
+func (z Zone) Filter(fn func(*Record) bool) []*Record {
+	var res []*Record
+	for _, r := range z.Records {
+		if r != nil && fn(r) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// ByType groups the records of the zone by their type
+func (z Zone) ByType() map[RecordType][]*Record {
+	res := make(map[RecordType][]*Record)
+	for _, r := range z.Filter(anyRecord) {
+		res[r.Type] = append(res[r.Type], r)
+	}
+	return res
+}
+
+// anyRecord selects all records when passed to Zone.Filter
+func anyRecord(*Record) bool {
+	return true
+}
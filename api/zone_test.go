@@ -0,0 +1,78 @@
+package api
+
+import "testing"
+
+var helperZone = Zone{
+	Name: "example.org",
+	Records: []*Record{
+		{ID: 1, Host: "@", Type: RecordTypeA, Data: "192.0.2.1"},
+		{ID: 2, Host: "www", Type: RecordTypeA, Data: "192.0.2.2"},
+		{ID: 3, Host: "WWW", Type: RecordTypeA, Data: "192.0.2.3"},
+		{ID: 4, Host: "www", Type: RecordTypeAAAA, Data: "2001:db8::1"},
+		{ID: 5, Host: "", Type: RecordTypeMX, Priority: 10, Data: "mail.example.org."},
+	},
+}
+
+func TestNormalizeHost(t *testing.T) {
+	for in, out := range map[string]string{
+		"":                 "@",
+		"@":                "@",
+		"example.org":      "@",
+		"Example.org.":     "@",
+		"www":              "www",
+		"www.example.org":  "www",
+		"www.example.org.": "www",
+		"a.b.example.org.": "a.b",
+		"www.example.net.": "www.example.net.",
+		"notexample.org":   "notexample.org",
+		"notexample.org.":  "notexample.org.",
+	} {
+		if h := helperZone.NormalizeHost(in); h != out {
+			t.Errorf("expected %q to normalize to %q, got %q", in, out, h)
+		}
+	}
+}
+
+func TestZoneFind(t *testing.T) {
+	if r := helperZone.Find("example.org.", RecordTypeA); r == nil || r.ID != 1 {
+		t.Errorf("expected the apex A record, got %+v", r)
+	}
+	if r := helperZone.Find("@", RecordTypeMX); r == nil || r.ID != 5 {
+		t.Errorf("expected the MX record, got %+v", r)
+	}
+	if r := helperZone.Find("ftp", RecordTypeA); r != nil {
+		t.Errorf("expected no record, got %+v", r)
+	}
+	if rs := helperZone.FindAll("www.example.org.", RecordTypeA); len(rs) != 2 {
+		t.Errorf("expected 2 A records for www, got %d", len(rs))
+	}
+}
+
+func TestZoneFilterAndByType(t *testing.T) {
+	rs := helperZone.Filter(func(r *Record) bool { return r.ID%2 == 0 })
+	if len(rs) != 2 {
+		t.Errorf("expected 2 records, got %d", len(rs))
+	}
+
+	bt := helperZone.ByType()
+	if len(bt[RecordTypeA]) != 3 || len(bt[RecordTypeAAAA]) != 1 || len(bt[RecordTypeMX]) != 1 {
+		t.Errorf("unexpected grouping %+v", bt)
+	}
+}
+
+func TestZoneNilRecords(t *testing.T) {
+	z := Zone{Name: "example.org", Records: append([]*Record{nil}, helperZone.Records...)}
+
+	if r := z.Find("www", RecordTypeAAAA); r == nil || r.ID != 4 {
+		t.Errorf("expected the AAAA record, got %+v", r)
+	}
+	if bt := z.ByType(); len(bt[RecordTypeA]) != 3 {
+		t.Errorf("unexpected grouping %+v", bt)
+	}
+	if _, err := soaRecord(z); err != ErrNoSOA {
+		t.Errorf("expected ErrNoSOA, got %v", err)
+	}
+	if p := Diff(z, z); !p.Empty() {
+		t.Errorf("expected nil records to be skipped, got %s", p)
+	}
+}
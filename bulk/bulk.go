@@ -0,0 +1,170 @@
+// Package bulk runs operations on all zones of an account, with limited
+// parallelism, per zone error collection, progress reporting and the
+// ability to resume an interrupted run from a checkpoint.
+//
+// All requests still pass through the scheduling of the eurodnsgo.Client,
+// so the parallelism only determines how many zones are in flight.
+package bulk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// Func is the operation performed on every zone
+type Func func(ctx context.Context, c eurodnsgo.Client, zone string) error
+
+// Progress describes the state of a run after a zone was processed
+type Progress struct {
+	// Zone is the zone which was just processed
+	Zone string
+	// Err holds the error returned for the zone
+	Err error
+	// Done is the number of zones processed so far, including skipped zones
+	Done  int
+	Total int
+}
+
+// Options configures a bulk run
+type Options struct {
+	// Parallelism is the number of zones processed concurrently, defaults
+	// to 1
+	Parallelism int
+	// Zones limits the run to the given zones instead of all zones
+	// returned by GetZoneList
+	Zones []string
+	// Progress is called after every zone, it is never called concurrently
+	Progress func(Progress)
+	// Checkpoint skips zones completed by an earlier run and records the
+	// zones completed by this run
+	Checkpoint Checkpoint
+}
+
+// Result holds the outcome of a bulk run
+type Result struct {
+	Total     int
+	Succeeded []string
+	// Skipped holds the zones already completed according to the checkpoint
+	Skipped []string
+	// Errors holds the error for every failed zone
+	Errors map[string]error
+}
+
+// Err returns an error summarizing all failed zones, or nil when all zones
+// succeeded
+func (r Result) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+
+	zones := make([]string, 0, len(r.Errors))
+	for z := range r.Errors {
+		zones = append(zones, z)
+	}
+	sort.Strings(zones)
+
+	msgs := make([]string, len(zones))
+	for i, z := range zones {
+		msgs[i] = fmt.Sprintf("%s: %s", z, r.Errors[z])
+	}
+	return fmt.Errorf("%d of %d zones failed: %s", len(zones), r.Total, strings.Join(msgs, "; "))
+}
+
+// Run performs the function on all zones. The returned error is only set
+// when the zone list could not be retrieved or the context was cancelled,
+// failures of individual zones are collected in the Result.
+func Run(ctx context.Context, c eurodnsgo.Client, fn Func, opts Options) (Result, error) {
+	ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns bulk.Run")
+	res, err := run(ctx, c, fn, opts)
+	span.SetAttributes(
+		eurodnsgo.Attr{Key: "eurodns.bulk.total", Value: res.Total},
+		eurodnsgo.Attr{Key: "eurodns.bulk.failed", Value: len(res.Errors)},
+	)
+	eurodnsgo.EndSpan(span, err)
+	return res, err
+}
+
+func run(ctx context.Context, c eurodnsgo.Client, fn Func, opts Options) (Result, error) {
+	res := Result{Errors: make(map[string]error)}
+
+	zones := opts.Zones
+	if zones == nil {
+		var err error
+		if zones, err = api.GetZoneList(ctx, c); err != nil {
+			return res, err
+		}
+	}
+	res.Total = len(zones)
+
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	var mu sync.Mutex
+	done := 0
+	report := func(zone string, err error) {
+		done++
+		if opts.Progress != nil {
+			opts.Progress(Progress{zone, err, done, res.Total})
+		}
+	}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for zone := range queue {
+				zctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns bulk.Zone", eurodnsgo.Attr{Key: eurodnsgo.AttrZone, Value: zone})
+				err := fn(zctx, c, zone)
+				eurodnsgo.EndSpan(span, err)
+				if err == nil && opts.Checkpoint != nil {
+					err = opts.Checkpoint.Mark(zone)
+				}
+
+				mu.Lock()
+				if err != nil {
+					res.Errors[zone] = err
+				} else {
+					res.Succeeded = append(res.Succeeded, zone)
+				}
+				report(zone, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for _, zone := range zones {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if opts.Checkpoint != nil && opts.Checkpoint.Done(zone) {
+			mu.Lock()
+			res.Skipped = append(res.Skipped, zone)
+			report(zone, nil)
+			mu.Unlock()
+			continue
+		}
+
+		select {
+		case queue <- zone:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return res, err
+}
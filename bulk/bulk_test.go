@@ -0,0 +1,128 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+var testZones = []string{"a.org", "b.org", "c.org", "d.org", "e.org"}
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	fn := func(ctx context.Context, c eurodnsgo.Client, zone string) error {
+		mu.Lock()
+		seen[zone] = true
+		mu.Unlock()
+		if zone == "c.org" {
+			return errors.New("failure")
+		}
+		return nil
+	}
+
+	var progress []Progress
+	res, err := Run(context.TODO(), nil, fn, Options{
+		Parallelism: 3,
+		Zones:       testZones,
+		Progress:    func(p Progress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 5 || len(res.Succeeded) != 4 || len(res.Errors) != 1 || res.Errors["c.org"] == nil {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if res.Err() == nil {
+		t.Error("expected the failed zone to be reported")
+	}
+	if len(progress) != 5 || progress[4].Done != 5 || progress[4].Total != 5 {
+		t.Errorf("unexpected progress %+v", progress)
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eurodnsgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint")
+
+	fail := true
+	fn := func(ctx context.Context, c eurodnsgo.Client, zone string) error {
+		if fail && zone == "d.org" {
+			return errors.New("failure")
+		}
+		return nil
+	}
+
+	cp, err := OpenFileCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Run(context.TODO(), nil, fn, Options{Zones: testZones, Checkpoint: cp}); err != nil {
+		t.Fatal(err)
+	}
+
+	// resuming from the file only processes the failed zone
+	fail = false
+	if cp, err = OpenFileCheckpoint(path); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Run(context.TODO(), nil, fn, Options{Zones: testZones, Checkpoint: cp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Skipped) != 4 || len(res.Succeeded) != 1 || res.Succeeded[0] != "d.org" {
+		t.Errorf("unexpected result %+v", res)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fn := func(ctx context.Context, c eurodnsgo.Client, zone string) error {
+		cancel()
+		return nil
+	}
+
+	res, err := Run(ctx, nil, fn, Options{Zones: testZones})
+	if err != context.Canceled {
+		t.Errorf("expected the run to be cancelled, got %v", err)
+	}
+	if len(res.Succeeded) == len(testZones) {
+		t.Error("expected the run to stop early")
+	}
+}
+
+func TestMutate(t *testing.T) {
+	z := api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{ID: 1, Host: "www", Type: api.RecordTypeA, TTL: 3600, Data: "192.0.2.1"},
+			{ID: 2, Host: "ftp", Type: api.RecordTypeA, TTL: 3600, Data: "192.0.2.2"},
+			{ID: 3, Host: "@", Type: api.RecordTypeMX, TTL: 3600, Priority: 10, Data: "mail.example.org."},
+		},
+	}
+
+	p := mutate(z, func(r *api.Record) {
+		if r.Data == "192.0.2.1" {
+			r.Data = "198.51.100.1"
+		}
+	})
+	changes := p.Records(api.Change)
+	if len(p.Actions) != 1 || changes[0].ID != 1 || changes[0].Data != "198.51.100.1" {
+		t.Errorf("unexpected plan %s", p)
+	}
+	if z.Records[0].Data != "192.0.2.1" {
+		t.Error("expected the zone to be left untouched")
+	}
+}
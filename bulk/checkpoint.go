@@ -0,0 +1,76 @@
+package bulk
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Checkpoint keeps track of the zones completed by a bulk run
+type Checkpoint interface {
+	// Done reports whether the zone was completed before
+	Done(zone string) bool
+	// Mark records the zone as completed
+	Mark(zone string) error
+}
+
+// FileCheckpoint is a Checkpoint storing completed zones in a file, one
+// zone per line
+type FileCheckpoint struct {
+	mu   sync.Mutex
+	path string
+	done map[string]bool
+}
+
+// OpenFileCheckpoint loads the completed zones from the file at the path,
+// which is created when it does not exist yet
+func OpenFileCheckpoint(path string) (*FileCheckpoint, error) {
+	cp := &FileCheckpoint{path: path, done: make(map[string]bool)}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if zone := strings.TrimSpace(s.Text()); zone != "" {
+			cp.done[zone] = true
+		}
+	}
+	return cp, s.Err()
+}
+
+// Done reports whether the zone was completed before
+func (cp *FileCheckpoint) Done(zone string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.done[zone]
+}
+
+// Mark records the zone as completed by appending it to the file
+func (cp *FileCheckpoint) Mark(zone string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	f, err := os.OpenFile(cp.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, zone); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	cp.done[zone] = true
+	return nil
+}
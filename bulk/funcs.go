@@ -0,0 +1,62 @@
+package bulk
+
+import (
+	"context"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// mutate applies the function to a copy of every record of the zone and
+// returns the Plan changing all modified records
+func mutate(z api.Zone, fn func(r *api.Record)) api.Plan {
+	desired := api.Zone{Name: z.Name}
+	for _, r := range z.Records {
+		c := *r
+		fn(&c)
+		desired.Records = append(desired.Records, &c)
+	}
+	return api.Diff(z, desired)
+}
+
+// Mutate returns a Func which applies the function to every record of a
+// zone, and submits all modified records in a single zone:update request.
+// Modified records are validated first, unless the context was created by
+// api.SkipValidation.
+func Mutate(fn func(r *api.Record)) Func {
+	return func(ctx context.Context, c eurodnsgo.Client, zone string) error {
+		z, err := api.GetZoneInfo(ctx, c, zone)
+		if err != nil {
+			return err
+		}
+
+		p := mutate(z, fn)
+		if !api.ValidationSkipped(ctx) {
+			for _, r := range p.Records(api.Change) {
+				if err := r.Validate(); err != nil {
+					return err
+				}
+			}
+		}
+		return api.ApplyPlan(ctx, c, p)
+	}
+}
+
+// ReplaceData returns a Func which replaces the data of all records
+// holding from by to, like an IP address during renumbering
+func ReplaceData(from, to string) Func {
+	return Mutate(func(r *api.Record) {
+		if r.Data == from {
+			r.Data = to
+		}
+	})
+}
+
+// SetTTL returns a Func which changes the TTL of all records of the type
+func SetTTL(t api.RecordType, ttl int) Func {
+	return Mutate(func(r *api.Record) {
+		if r.Type == t {
+			r.TTL = ttl
+		}
+	})
+}
@@ -0,0 +1,147 @@
+// Package cache wraps a eurodnsgo.Client to serve read requests like
+// GetZoneList and GetZoneInfo from a local snapshot.
+//
+// Responses of read requests are kept for a configurable TTL in a
+// pluggable Store. Any other request passing through the cache, like the
+// zone:update performed by api.ZoneRecordAdd, clears the store so reads
+// following a write are always consistent with it.
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// DefaultTTL is the time responses are cached when no TTL is configured
+const DefaultTTL = 5 * time.Minute
+
+// Options configures a caching Client
+type Options struct {
+	// TTL is the time responses are cached, defaults to DefaultTTL
+	TTL time.Duration
+	// Store keeps the cached responses, defaults to a MemoryStore
+	Store Store
+}
+
+// Client is a eurodnsgo.Client serving read requests from a cache
+type Client struct {
+	// generation is incremented by every write, so reads which were in
+	// flight during a write do not store stale responses
+	generation uint64
+
+	next  eurodnsgo.Client
+	ttl   time.Duration
+	store Store
+}
+
+// New wraps the client with a cache
+func New(c eurodnsgo.Client, opts Options) *Client {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+
+	return &Client{
+		next:  c,
+		ttl:   opts.TTL,
+		store: opts.Store,
+	}
+}
+
+// Tracer returns the Tracer of the wrapped client, so operations of the api
+// package are traced like the calls of the wrapped client
+func (c *Client) Tracer() eurodnsgo.Tracer {
+	return eurodnsgo.TracerOf(c.next)
+}
+
+func requestKey(sr *eurodnsgo.SoapRequest) string {
+	return sr.PrepareContent()
+}
+
+// Schedule returns cached responses for read requests, and schedules all
+// other requests on the wrapped client
+func (c *Client) Schedule(ctx context.Context, sr *eurodnsgo.SoapRequest) (chan []byte, error) {
+	if !sr.ReadOnly() {
+		return c.scheduleWrite(ctx, sr)
+	}
+
+	key := requestKey(sr)
+	if e, ok := c.store.Get(key); ok && time.Now().Before(e.Expires) {
+		if err := sr.DecodeResult(e.Data); err == nil {
+			r := make(chan []byte, 1)
+			r <- e.Data
+			return r, nil
+		}
+	}
+
+	gen := atomic.LoadUint64(&c.generation)
+	ch, err := c.next.Schedule(ctx, sr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(chan []byte, 1)
+	go func() {
+		b := <-ch
+		if sr.Err() == nil && atomic.LoadUint64(&c.generation) == gen {
+			// a failing store only costs a cache miss later on
+			_ = c.store.Set(key, Entry{b, time.Now().Add(c.ttl)})
+		}
+		r <- b
+	}()
+	return r, nil
+}
+
+func (c *Client) scheduleWrite(ctx context.Context, sr *eurodnsgo.SoapRequest) (chan []byte, error) {
+	ch, err := c.next.Schedule(ctx, sr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(chan []byte, 1)
+	go func() {
+		b := <-ch
+		c.invalidate()
+		r <- b
+	}()
+	return r, nil
+}
+
+// Call performs the request on the wrapped client directly, clearing the
+// cache for anything but read requests
+func (c *Client) Call(ctx context.Context, sr *eurodnsgo.SoapRequest) error {
+	err := c.next.Call(ctx, sr)
+	if !sr.ReadOnly() {
+		c.invalidate()
+	}
+	return err
+}
+
+func (c *Client) invalidate() {
+	atomic.AddUint64(&c.generation, 1)
+	_ = c.store.Clear()
+}
+
+// InvalidateZone drops the cached zone list and the cached information of
+// the zone, to be used after changing the zone through another client
+func (c *Client) InvalidateZone(name string) error {
+	list := eurodnsgo.NewSoapRequest("zone", "list", nil)
+	info := eurodnsgo.NewSoapRequest("zone", "info", nil)
+	info.AddParam(eurodnsgo.NewParam("zone", "name", name))
+
+	if err := c.store.Delete(requestKey(list)); err != nil {
+		return err
+	}
+	return c.store.Delete(requestKey(info))
+}
+
+// InvalidateAll drops all cached responses
+func (c *Client) InvalidateAll() error {
+	atomic.AddUint64(&c.generation, 1)
+	return c.store.Clear()
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omines/eurodnsgo"
+)
+
+// countingClient answers every request with a zone name and counts the
+// requests which reach it
+type countingClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingClient) Schedule(ctx context.Context, sr *eurodnsgo.SoapRequest) (chan []byte, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+
+	b := []byte("<zone:name>example.org</zone:name>")
+	if err := sr.DecodeResult(b); err != nil {
+		return nil, err
+	}
+	ch := make(chan []byte, 1)
+	ch <- b
+	return ch, nil
+}
+
+func (c *countingClient) Call(ctx context.Context, sr *eurodnsgo.SoapRequest) error {
+	_, err := c.Schedule(ctx, sr)
+	return err
+}
+
+type zoneName struct {
+	XMLName xml.Name `xml:"resData"`
+	Name    string   `xml:"zone name"`
+}
+
+func zoneInfo(t *testing.T, c eurodnsgo.Client, name string) string {
+	var v zoneName
+	sr := eurodnsgo.NewSoapRequest("zone", "info", &v)
+	sr.AddParam(eurodnsgo.NewParam("zone", "name", name))
+
+	ch, err := c.Schedule(context.TODO(), sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	return v.Name
+}
+
+func zoneUpdate(t *testing.T, c eurodnsgo.Client) {
+	var v interface{}
+	ch, err := c.Schedule(context.TODO(), eurodnsgo.NewSoapRequest("zone", "update", &v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+}
+
+func testCache(t *testing.T, store Store) {
+	next := &countingClient{}
+	c := New(next, Options{Store: store})
+
+	for i := 0; i < 3; i++ {
+		if name := zoneInfo(t, c, "example.org"); name != "example.org" {
+			t.Fatalf("expected the result to be decoded, got %q", name)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("expected a single request, got %d", next.calls)
+	}
+
+	zoneInfo(t, c, "example.net")
+	if next.calls != 2 {
+		t.Errorf("expected other zones to be requested, got %d requests", next.calls)
+	}
+
+	zoneUpdate(t, c)
+	zoneInfo(t, c, "example.org")
+	if next.calls != 4 {
+		t.Errorf("expected writes to clear the cache, got %d requests", next.calls)
+	}
+
+	if err := c.InvalidateZone("example.org"); err != nil {
+		t.Fatal(err)
+	}
+	zoneInfo(t, c, "example.org")
+	if next.calls != 5 {
+		t.Errorf("expected the zone to be invalidated, got %d requests", next.calls)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testCache(t, NewMemoryStore())
+}
+
+func TestDiskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eurodnsgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewDiskStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCache(t, store)
+}
+
+func TestExpiry(t *testing.T) {
+	next := &countingClient{}
+	c := New(next, Options{TTL: time.Millisecond})
+
+	zoneInfo(t, c, "example.org")
+	time.Sleep(5 * time.Millisecond)
+	zoneInfo(t, c, "example.org")
+	if next.calls != 2 {
+		t.Errorf("expected expired entries to be requested again, got %d requests", next.calls)
+	}
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response
+type Entry struct {
+	Data    []byte
+	Expires time.Time
+}
+
+// Store keeps cached responses by key
+type Store interface {
+	// Get returns the entry for the key, expired entries may be returned
+	Get(key string) (Entry, bool)
+	Set(key string, e Entry) error
+	Delete(key string) error
+	// Clear removes all entries
+	Clear() error
+}
+
+// MemoryStore is a Store keeping entries in memory
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]Entry
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]Entry)}
+}
+
+// Get returns the entry for the key
+func (s *MemoryStore) Get(key string) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[key]
+	return e, ok
+}
+
+// Set stores the entry for the key
+func (s *MemoryStore) Set(key string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = e
+	return nil
+}
+
+// Delete removes the entry for the key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Clear removes all entries
+func (s *MemoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]Entry)
+	return nil
+}
+
+// DiskStore is a Store keeping every entry in a file inside a directory,
+// so the cache survives restarts and can be shared between processes
+type DiskStore struct {
+	dir string
+}
+
+// diskStoreExt is the extension of the files written by a DiskStore
+const diskStoreExt = ".cache"
+
+// NewDiskStore returns a DiskStore in the directory, which is created when
+// it does not exist yet
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir}, nil
+}
+
+func (s *DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+diskStoreExt)
+}
+
+// Get returns the entry for the key
+func (s *DiskStore) Get(key string) (Entry, bool) {
+	var e Entry
+
+	b, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		return e, false
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return e, false
+	}
+	return e, true
+}
+
+// Set stores the entry for the key
+func (s *DiskStore) Set(key string, e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so readers never see partial entries
+	tmp, err := ioutil.TempFile(s.dir, "tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Delete removes the entry for the key
+func (s *DiskStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Clear removes all entries
+func (s *DiskStore) Clear() error {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f.Name(), diskStoreExt) {
+			if err := os.Remove(filepath.Join(s.dir, f.Name())); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
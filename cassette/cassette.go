@@ -0,0 +1,280 @@
+// Package cassette records the HTTP interactions of a eurodnsgo.Client to
+// a file and replays them later, so tests can run against a captured real
+// session without network access or credentials.
+//
+// A Recorder is an http.RoundTripper which is set as transport of the
+// HTTPClient in the eurodnsgo.ClientConfig:
+//
+//	rec, err := cassette.New("testdata/zones.json", cassette.ModeReplayOrRecord, nil)
+//	defer rec.Save()
+//
+//	c, err := eurodnsgo.NewClient(eurodnsgo.ClientConfig{
+//		Host:       host,
+//		Username:   username,
+//		Password:   password,
+//		HTTPClient: rec.Client(),
+//	})
+//
+// Requests are matched on namespace, method and the normalized request
+// XML. Identical requests are replayed in the order they were recorded, so
+// a zone:info before and after a zone:update return different zones.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Version is the version of the cassette file format
+const Version = 1
+
+// Redacted replaces the values of credential headers in recordings
+const Redacted = "REDACTED"
+
+// redactedHeaders are never stored in a cassette
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// ErrNoInteraction is returned when replaying a request which was not
+// recorded, or of which all recordings were already replayed
+var ErrNoInteraction = errors.New("cassette: no recorded interaction for request")
+
+// Mode determines whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay replays recorded interactions and never sends requests
+	ModeReplay Mode = iota
+	// ModeRecord sends all requests and records the interactions
+	ModeRecord
+	// ModeReplayOrRecord replays the cassette when its file exists, and
+	// records a new cassette otherwise
+	ModeReplayOrRecord
+)
+
+// Cassette holds recorded interactions
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request with its response
+type Interaction struct {
+	Namespace string   `json:"namespace"`
+	Method    string   `json:"method"`
+	Request   Request  `json:"request"`
+	Response  Response `json:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Header http.Header `json:"header"`
+	// Body holds the normalized request XML
+	Body string `json:"body"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %s", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("cassette %s: unsupported version %d", path, c.Version)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to a file, creating its directory when needed
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// Recorder is an http.RoundTripper recording or replaying a cassette
+type Recorder struct {
+	path string
+	next http.RoundTripper
+
+	mu        sync.Mutex
+	recording bool
+	cassette  *Cassette
+	replayed  []bool
+}
+
+// New returns a Recorder for the cassette file. Requests are recorded
+// through the next transport, which defaults to http.DefaultTransport.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{path: path, next: next}
+
+	if mode == ModeReplayOrRecord {
+		mode = ModeReplay
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			mode = ModeRecord
+		}
+	}
+
+	if mode == ModeRecord {
+		r.recording = true
+		r.cassette = &Cassette{Version: Version}
+		return r, nil
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	r.cassette = c
+	r.replayed = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Recording reports whether the recorder records interactions, instead of
+// replaying them
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns an http.Client using the recorder as transport
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Save writes the recorded interactions to the cassette file, it does
+// nothing when replaying
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	namespace, method, normalized, err := normalize(body)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %s", err)
+	}
+
+	if !r.recording {
+		return r.replay(req, namespace, method, normalized)
+	}
+
+	// the request is cloned, as transports must not modify it
+	out := req.WithContext(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res, err := r.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Namespace: namespace,
+		Method:    method,
+		Request:   Request{redact(req.Header), normalized},
+		Response:  Response{res.StatusCode, redact(res.Header), string(resBody)},
+	})
+	r.mu.Unlock()
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, namespace, method, normalized string) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.replayed[i] || in.Namespace != namespace || in.Method != method || in.Request.Body != normalized {
+			continue
+		}
+		r.replayed[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%s: %s:%s", ErrNoInteraction, namespace, method)
+}
+
+// Pending returns the interactions which were not replayed yet, to verify
+// that a test performed all recorded requests
+func (r *Recorder) Pending() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []Interaction
+	for i, in := range r.cassette.Interactions {
+		if i < len(r.replayed) && !r.replayed[i] {
+			pending = append(pending, in)
+		}
+	}
+	return pending
+}
+
+func redact(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := c[http.CanonicalHeaderKey(k)]; ok {
+			c.Set(k, Redacted)
+		}
+	}
+	return c
+}
@@ -0,0 +1,130 @@
+package cassette
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+	"github.com/omines/eurodnsgo/eurodnstest"
+)
+
+func envelope(doc string) []byte {
+	return []byte("xml=" + url.QueryEscape(url.QueryEscape(doc)))
+}
+
+func TestNormalize(t *testing.T) {
+	a := `<?xml version="1.0" encoding="UTF-8"?>
+<request xmlns:zone="http://www.eurodns.com/zone">
+	<zone:info>
+		<zone:name>example.org</zone:name>
+	</zone:info>
+</request>`
+	b := `<request xmlns:zone="http://www.eurodns.com/zone"><zone:info><zone:name> example.org </zone:name></zone:info></request>`
+
+	ns, method, na, err := normalize(envelope(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns != "zone" || method != "info" {
+		t.Errorf("unexpected operation %s:%s", ns, method)
+	}
+	_, _, nb, err := normalize(envelope(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if na != nb {
+		t.Errorf("expected equal normalized documents, got\n%s\n%s", na, nb)
+	}
+
+	if _, _, _, err := normalize(envelope("<request></request>")); err == nil {
+		t.Error("expected an error for a request without operation")
+	}
+}
+
+func session(t *testing.T, c eurodnsgo.Client) api.Zone {
+	ctx := context.TODO()
+
+	z, err := api.GetZoneInfo(ctx, c, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ZoneRecordAdd(ctx, c, z, api.Record{Host: "www", Type: api.RecordTypeA, Data: "192.0.2.1", TTL: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	z, err = api.GetZoneInfo(ctx, c, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return z
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eurodnsgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "session.json")
+
+	srv := eurodnstest.NewServer()
+	srv.AddZone(api.Zone{Name: "example.org"})
+	cc := srv.Config()
+
+	rec, err := New(path, ModeReplayOrRecord, cc.HTTPClient.Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Recording() {
+		t.Fatal("expected to record a missing cassette")
+	}
+	cc.HTTPClient = rec.Client()
+	c, _ := eurodnsgo.NewClient(cc)
+	recorded := session(t, c)
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "Basic ") || !strings.Contains(string(b), Redacted) {
+		t.Error("expected the credentials to be redacted")
+	}
+
+	// the server is closed, so any request not replayed fails
+	rec, err = New(path, ModeReplayOrRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Recording() {
+		t.Fatal("expected to replay an existing cassette")
+	}
+	cc.HTTPClient = rec.Client()
+	c, _ = eurodnsgo.NewClient(cc)
+	replayed := session(t, c)
+
+	if len(replayed.Records) != 1 || replayed.Records[0].Data != recorded.Records[0].Data {
+		t.Errorf("expected the recorded zone, got %+v", replayed)
+	}
+	if n := len(rec.Pending()); n != 0 {
+		t.Errorf("expected all interactions to be replayed, %d pending", n)
+	}
+
+	if _, err := api.GetZoneList(context.TODO(), c); err == nil || !strings.Contains(err.Error(), ErrNoInteraction.Error()) {
+		t.Errorf("expected unrecorded requests to fail, got %v", err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join("testdata", "missing.json"), ModeReplay, nil); err == nil {
+		t.Error("expected an error for a missing cassette")
+	}
+}
@@ -0,0 +1,83 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// normalize decodes the form encoded envelope of a request and returns its
+// namespace, method and XML document without insignificant whitespace and
+// with sorted attributes
+func normalize(body []byte) (namespace, method, normalized string, err error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return "", "", "", err
+	}
+	// the client escapes the envelope twice, parsing the form undoes once
+	doc, err := url.QueryUnescape(form.Get("xml"))
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var buf bytes.Buffer
+	depth := 0
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			// the operation is the first element inside the request
+			if depth == 2 && method == "" {
+				namespace, method = t.Name.Space, t.Name.Local
+			}
+			writeStart(&buf, t)
+		case xml.EndElement:
+			depth--
+			buf.WriteString("</" + qualifiedName(t.Name) + ">")
+		case xml.CharData:
+			if s := strings.TrimSpace(string(t)); s != "" {
+				xml.EscapeText(&buf, []byte(s))
+			}
+		}
+	}
+
+	if method == "" {
+		return "", "", "", errors.New("request contains no operation")
+	}
+	return namespace, method, buf.String(), nil
+}
+
+func writeStart(buf *bytes.Buffer, t xml.StartElement) {
+	attrs := append([]xml.Attr(nil), t.Attr...)
+	sort.Slice(attrs, func(i, j int) bool {
+		return qualifiedName(attrs[i].Name) < qualifiedName(attrs[j].Name)
+	})
+
+	buf.WriteString("<" + qualifiedName(t.Name))
+	for _, a := range attrs {
+		buf.WriteString(" " + qualifiedName(a.Name) + `="`)
+		xml.EscapeText(buf, []byte(a.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+}
+
+func qualifiedName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
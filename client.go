@@ -3,14 +3,10 @@ package eurodnsgo
 import (
 	"context"
 	"errors"
-	"log"
+	"net/http"
 	"time"
 )
 
-// The delay between calls in milliseconds between scheduled
-// requests.
-const defaultCallDelay = 500
-
 // ClientConfig represents the data needed to connect to the API
 type ClientConfig struct {
 	// Set the Host to connect to to use the API
@@ -19,14 +15,45 @@ type ClientConfig struct {
 	Username string
 	// Set the Password to connect to the API
 	Password string
-	// The CallDelay regulates the schedule iteration speed
-	// in milliseconds. Defaults to 500 milliseconds.
+	// Credentials provides the credentials for every request instead of
+	// the Username and Password, so they can be rotated. See
+	// EnvCredentials and FileCredentials.
+	Credentials CredentialsProvider
+	// CallDelay is ignored.
+	//
+	// Deprecated: scheduled calls are performed one at a time, without a
+	// delay between them.
 	CallDelay int
+	// HTTPClient performs the HTTP requests, defaults to a new http.Client.
+	// It can be set to configure timeouts, proxies or custom transports.
+	HTTPClient *http.Client
+	// Middleware wraps every call to the API, the first middleware is the
+	// outermost. See Middleware.
+	Middleware []Middleware
+	// Logger receives an entry for every call, defaults to a Logger writing
+	// failed calls to the standard logger of the log package. Use NopLogger
+	// to silence the client.
+	Logger Logger
+	// Metrics receives measurements of all calls and of the queue of
+	// scheduled calls
+	Metrics Metrics
+	// Tracer starts a span for every call, and for the operations of the
+	// api package performed with the client. Calls are not traced when nil.
+	Tracer Tracer
+	// QueueSize is the number of scheduled calls which can wait in every
+	// priority lane, defaults to 32. Schedule returns ErrQueueFull when the
+	// lane of a call is full.
+	QueueSize int
+	// Coalesce shares the result of a read request in progress with
+	// identical read requests of the same priority, instead of performing
+	// every request
+	Coalesce bool
 }
 
 // Client defines the functions needed to do a remote request
 type Client interface {
-	// Schedule schedules a request to be send to the XML server
+	// Schedule schedules a request to be send to the XML server, in the
+	// lane of the priority set by WithPriority
 	Schedule(context.Context, *SoapRequest) (chan []byte, error)
 	// Call performs a request to the XML server
 	Call(context.Context, *SoapRequest) error
@@ -37,47 +64,156 @@ type scheduledCall struct {
 	result chan []byte
 	// Context is required in this struct since it is passed through a channel
 	ctx context.Context
+	// queued is the time the call entered the schedule
+	queued time.Time
+	// flight is landed once the call completed
+	flight *flight
 }
 
 type client struct {
 	sc           *soapClient
-	callDelay    int
-	callSchedule chan scheduledCall
+	callSchedule *lanes
+	metrics      Metrics
+	tracer       Tracer
+	// flights is nil unless coalescing is enabled
+	flights *flights
+}
+
+// join returns the flight of an identical read request in progress in the
+// lane, or starts a new flight when the second return value is true
+func (c *client) join(sr *SoapRequest, lane int) (*flight, bool) {
+	if c.flights == nil {
+		return nil, true
+	}
+	return c.flights.join(sr, lane)
+}
+
+func (c *client) land(ctx context.Context, f *flight, result []byte, err error) {
+	if c.flights != nil {
+		c.flights.land(ctx, f, result, err)
+	}
+}
+
+// Tracer returns the Tracer of the client, see TracerOf
+func (c *client) Tracer() Tracer {
+	return c.tracer
 }
 
-// Schedule schedules a request to be send to the EuroDNS server
+// startSpan starts the span covering a call, including the time spent in
+// the queue for scheduled calls
+func (c *client) startSpan(ctx context.Context, sr *SoapRequest, scheduled bool) (context.Context, Span) {
+	return startSpan(c.Tracer(), ctx, "eurodns "+sr.Namespace+":"+sr.Method,
+		Attr{AttrNamespace, sr.Namespace},
+		Attr{AttrMethod, sr.Method},
+		Attr{AttrScheduled, scheduled},
+	)
+}
+
+// Schedule schedules a request to be send to the EuroDNS server. Calls
+// wait in the lane of their priority, see WithPriority. With Coalesce set,
+// read requests identical to a read request in progress in the same lane
+// share its result.
 func (c *client) Schedule(ctx context.Context, sr *SoapRequest) (chan []byte, error) {
 	r := make(chan []byte, 1)
-	// will be processed inside client::run
-	c.callSchedule <- scheduledCall{sr, r, ctx}
+
+	f, leader := c.join(sr, int(PriorityFromContext(ctx)))
+	if !leader {
+		go c.follow(ctx, sr, r, f)
+		return r, nil
+	}
+
+	if err := c.enqueue(ctx, sr, r, f); err != nil {
+		return nil, err
+	}
 	return r, nil
 }
 
+// enqueue pushes a request in the lane of its priority, the flight is
+// landed by client::run once the call completed
+func (c *client) enqueue(ctx context.Context, sr *SoapRequest, r chan []byte, f *flight) error {
+	ctx, span := c.startSpan(ctx, sr, true)
+
+	// will be processed inside client::run
+	if err := c.callSchedule.push(PriorityFromContext(ctx), scheduledCall{sr, r, ctx, time.Now(), f}); err != nil {
+		EndSpan(span, err)
+		c.land(ctx, f, nil, err)
+		return err
+	}
+	c.metrics.SetQueueDepth(c.callSchedule.len())
+	return nil
+}
+
+// follow waits for the flight of an identical scheduled request, and
+// schedules the request itself when that request was abandoned
+func (c *client) follow(ctx context.Context, sr *SoapRequest, r chan []byte, f *flight) {
+	for {
+		b, err := f.wait(ctx, sr)
+		if err != errAbandoned {
+			sr.err = err
+			r <- b
+			return
+		}
+
+		var leader bool
+		f, leader = c.join(sr, int(PriorityFromContext(ctx)))
+		if !leader {
+			continue
+		}
+		if err := c.enqueue(ctx, sr, r, f); err != nil {
+			sr.err = err
+			r <- []byte{}
+		}
+		return
+	}
+}
+
 // Call performs a request at the EuroDNS server directly. The use
 // of Schedule is advised to prevent flooding the server with
 // requests
 func (c *client) Call(ctx context.Context, sr *SoapRequest) error {
-	_, err := c.makeCall(ctx, sr)
-	return err
+	for {
+		f, leader := c.join(sr, callLane)
+		if leader {
+			ctx, span := c.startSpan(ctx, sr, false)
+			b, err := c.makeCall(ctx, sr)
+			EndSpan(span, err)
+			c.land(ctx, f, b, err)
+			return err
+		}
+
+		// the request is performed again when the identical request
+		// in progress was abandoned
+		if _, err := f.wait(ctx, sr); err != errAbandoned {
+			return err
+		}
+	}
 }
 
 func (c *client) run() {
 	for {
-		select {
-		case sc := <-c.callSchedule:
-			b, err := c.makeCall(sc.ctx, sc.sr)
-			if err != nil {
-				log.Println("Error calling API")
-				log.Println(err)
-				sc.result <- []byte{}
-				break
-			}
-			sc.result <- b
-			time.Sleep(time.Duration(c.callDelay) * time.Millisecond)
-		default:
+		sc, ok := c.callSchedule.pop()
+		if !ok {
 			// cap the process
 			time.Sleep(50 * time.Millisecond)
+			continue
 		}
+
+		wait := time.Since(sc.queued)
+		c.metrics.SetQueueDepth(c.callSchedule.len())
+		c.metrics.ObserveQueueWait(wait)
+
+		span := SpanFromContext(sc.ctx)
+		span.SetAttributes(Attr{AttrQueueWait, wait.Nanoseconds() / int64(time.Millisecond)})
+		b, err := c.makeCall(sc.ctx, sc.sr)
+		EndSpan(span, err)
+		c.land(sc.ctx, sc.flight, b, err)
+		if err != nil {
+			// the error was logged by the logging middleware
+			sc.sr.err = err
+			sc.result <- []byte{}
+			continue
+		}
+		sc.result <- b
 	}
 }
 
@@ -88,34 +224,61 @@ func (c *client) makeCall(ctx context.Context, sr *SoapRequest) ([]byte, error)
 // NewClient returns a new client with the appropriate credentials
 // setup.
 func NewClient(cc ClientConfig) (Client, error) {
-	if len(cc.Username) == 0 {
-		return nil, errors.New("A username should be provided")
-	}
+	credentials := cc.Credentials
+	if credentials == nil {
+		if len(cc.Username) == 0 {
+			return nil, errors.New("A username should be provided")
+		}
 
-	if len(cc.Password) == 0 {
-		return nil, errors.New("A password should be provided")
+		if len(cc.Password) == 0 {
+			return nil, errors.New("A password should be provided")
+		}
+
+		credentials = StaticCredentials(cc.Username, cc.Password)
 	}
 
 	if len(cc.Host) == 0 {
 		return nil, errors.New("A host URL should be provided")
 	}
 
-	// callDelay in milliseconds between scheduled calls
-	callDelay := defaultCallDelay
-	if cc.CallDelay > 0 {
-		callDelay = cc.CallDelay
+	queueSize := defaultQueueSize
+	if cc.QueueSize > 0 {
+		queueSize = cc.QueueSize
+	}
+
+	httpClient := cc.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
 	}
 
 	sc := &soapClient{
-		cc.Username,
-		cc.Password,
+		credentials,
 		cc.Host,
-		callDelay,
+		httpClient,
+		nil,
+	}
+	logger := cc.Logger
+	if logger == nil {
+		logger = NewStdLogger(nil, false)
+	}
+	metrics := cc.Metrics
+	if metrics == nil {
+		metrics = nopMetrics{}
 	}
+	middleware := append(append([]Middleware(nil), cc.Middleware...), measuring(metrics), tracing(), logging(logger))
+	sc.handler = chain(sc.send, middleware)
 
 	c := &client{
 		sc:           sc,
-		callSchedule: make(chan scheduledCall, 32),
+		callSchedule: newLanes(queueSize),
+		metrics:      metrics,
+		tracer:       cc.Tracer,
+	}
+	if c.tracer == nil {
+		c.tracer = nopTracer{}
+	}
+	if cc.Coalesce {
+		c.flights = &flights{}
 	}
 	go c.run()
 
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+	"github.com/omines/eurodnsgo/zonefile"
+	"gopkg.in/yaml.v3"
+)
+
+// codeNotFound is the result code for objects which do not exist
+const codeNotFound = 2303
+
+// parse takes the zone or domain from the first argument and parses the
+// remaining arguments with fs
+func (s *session) parse(fs *flag.FlagSet, args []string) (string, error) {
+	fs.SetOutput(s.stderr)
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Parse(args)
+		return "", fmt.Errorf("missing %s name", fs.Name())
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return "", err
+	}
+	if fs.NArg() > 0 {
+		return "", fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+	return args[0], nil
+}
+
+func noArgs(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+	return nil
+}
+
+func domainsList(ctx context.Context, s *session, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+	domains, err := s.svc.Domains.List(ctx)
+	if err != nil {
+		return err
+	}
+	return s.writeNames(domains)
+}
+
+func zonesList(ctx context.Context, s *session, args []string) error {
+	if err := noArgs(args); err != nil {
+		return err
+	}
+	zones, err := s.svc.Zones.List(ctx)
+	if err != nil {
+		return err
+	}
+	return s.writeNames(zones)
+}
+
+// domainStatus is the result of domain info
+type domainStatus struct {
+	Domain  string                 `json:"domain"`
+	Managed bool                   `json:"managed"`
+	Zone    bool                   `json:"zone"`
+	Records map[api.RecordType]int `json:"records,omitempty"`
+}
+
+func domainInfo(ctx context.Context, s *session, args []string) error {
+	name, err := s.parse(flag.NewFlagSet("domain", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+
+	domains, err := s.svc.Domains.List(ctx)
+	if err != nil {
+		return err
+	}
+	st := domainStatus{Domain: name}
+	for _, d := range domains {
+		if strings.EqualFold(d, name) {
+			st.Managed = true
+		}
+	}
+
+	z, err := s.svc.Zones.Info(ctx, name)
+	if e, ok := err.(*eurodnsgo.Error); ok && e.Code == codeNotFound {
+		err = nil
+	} else if err == nil {
+		st.Zone = true
+		st.Records = make(map[api.RecordType]int)
+		for _, r := range z.Records {
+			st.Records[r.Type]++
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.format == formatJSON {
+		return s.writeJSON(st)
+	}
+	fmt.Fprintf(s.stdout, "domain:  %s\nmanaged: %t\nzone:    %t\n", st.Domain, st.Managed, st.Zone)
+	if st.Zone {
+		fmt.Fprintf(s.stdout, "records: %d", len(z.Records))
+		sep := " ("
+		for _, r := range z.Records {
+			if n, ok := st.Records[r.Type]; ok {
+				fmt.Fprintf(s.stdout, "%s%d %s", sep, n, r.Type)
+				delete(st.Records, r.Type)
+				sep = ", "
+			}
+		}
+		if sep != " (" {
+			fmt.Fprint(s.stdout, ")")
+		}
+		fmt.Fprintln(s.stdout)
+	}
+	return nil
+}
+
+func zoneShow(ctx context.Context, s *session, args []string) error {
+	name, err := s.parse(flag.NewFlagSet("zone", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	z, err := s.svc.Zones.Info(ctx, name)
+	if err != nil {
+		return err
+	}
+	return s.writeZone(z)
+}
+
+// Zone file formats of zone export and zone import
+const (
+	fileZone = "zone"
+	fileJSON = "json"
+	fileYAML = "yaml"
+)
+
+// fileFormat returns the zone file format for a file name
+func fileFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return fileJSON
+	case ".yaml", ".yml":
+		return fileYAML
+	}
+	return fileZone
+}
+
+func zoneExport(ctx context.Context, s *session, args []string) error {
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	format := fs.String("format", "", "file `format`: zone, json or yaml, defaults to the extension of -file or zone")
+	file := fs.String("file", "", "write to `path` instead of standard output")
+	name, err := s.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = fileFormat(*file)
+	}
+
+	z, err := s.svc.Zones.Info(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	switch *format {
+	case fileZone:
+		b, err = zonefile.Marshal(z)
+	case fileJSON:
+		b, err = json.MarshalIndent(z, "", "  ")
+		b = append(b, '\n')
+	case fileYAML:
+		b, err = yaml.Marshal(z)
+	default:
+		err = fmt.Errorf("unknown file format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *file == "" {
+		_, err = s.stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(*file, b, 0644)
+}
+
+// readZone reads the desired state of a zone from a file, JSON and YAML
+// files are read by api.LoadZone
+func readZone(name, path string) (api.Zone, error) {
+	if fileFormat(path) != fileZone {
+		z, err := api.LoadZone(path)
+		if err == nil && z.Name != name {
+			err = fmt.Errorf("%s contains zone %s, not %s", path, z.Name, name)
+		}
+		return z, err
+	}
+
+	z := api.Zone{Name: name}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return z, err
+	}
+
+	records, err := zonefile.Parse(bytes.NewReader(b), name)
+	if err != nil {
+		return z, err
+	}
+	for i := range records {
+		z.Records = append(z.Records, &records[i])
+	}
+	return z, nil
+}
+
+func zoneImport(ctx context.Context, s *session, args []string) error {
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	file := fs.String("file", "", "read the desired zone from `path`, a zone file unless it has a .json, .yaml or .yml extension")
+	dryRun := fs.Bool("dry-run", false, "only show the changes")
+	name, err := s.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	desired, err := readZone(name, *file)
+	if err != nil {
+		return err
+	}
+	p, err := s.svc.Zones.Plan(ctx, desired)
+	if err != nil {
+		return err
+	}
+
+	if s.format == formatJSON {
+		err = s.writeJSON(p)
+	} else {
+		_, err = fmt.Fprint(s.stdout, p)
+	}
+	if err != nil || *dryRun || p.Empty() {
+		return err
+	}
+	return s.svc.Zones.Apply(ctx, p)
+}
+
+// recordFlags defines the flags describing a record
+type recordFlags struct {
+	host     *string
+	typ      *string
+	data     *string
+	ttl      *int
+	priority *int
+}
+
+func newRecordFlags(fs *flag.FlagSet) recordFlags {
+	return recordFlags{
+		host:     fs.String("host", "", "`host` relative to the zone, empty for the apex"),
+		typ:      fs.String("type", "", "record `type`, like A or MX"),
+		data:     fs.String("data", "", "record `data`"),
+		ttl:      fs.Int("ttl", 0, "time to live in `seconds`"),
+		priority: fs.Int("priority", 0, "`priority` of MX and SRV records"),
+	}
+}
+
+// apply sets the fields of the record for the flags which were set
+func (f recordFlags) apply(fs *flag.FlagSet, r *api.Record) {
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "host":
+			r.Host = *f.host
+		case "type":
+			r.Type = api.RecordType(strings.ToUpper(*f.typ))
+		case "data":
+			r.Data = *f.data
+		case "ttl":
+			r.TTL = *f.ttl
+		case "priority":
+			r.Priority = *f.priority
+		}
+	})
+}
+
+// findRecord returns the zone and the record with the given ID inside it
+func (s *session) findRecord(ctx context.Context, name string, id int) (api.Zone, api.Record, error) {
+	if id <= 0 {
+		return api.Zone{}, api.Record{}, errors.New("-id is required")
+	}
+	z, err := s.svc.Zones.Info(ctx, name)
+	if err != nil {
+		return z, api.Record{}, err
+	}
+	for _, r := range z.Records {
+		if r.ID == id {
+			return z, *r, nil
+		}
+	}
+	return z, api.Record{}, fmt.Errorf("zone %s has no record %d", name, id)
+}
+
+func recordAdd(ctx context.Context, s *session, args []string) error {
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	rf := newRecordFlags(fs)
+	name, err := s.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *rf.typ == "" || *rf.data == "" {
+		return errors.New("-type and -data are required")
+	}
+
+	z, err := s.svc.Zones.Info(ctx, name)
+	if err != nil {
+		return err
+	}
+	var r api.Record
+	rf.apply(fs, &r)
+	return s.svc.Records.Add(ctx, z, r)
+}
+
+func recordChange(ctx context.Context, s *session, args []string) error {
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	id := fs.Int("id", 0, "`ID` of the record")
+	rf := newRecordFlags(fs)
+	name, err := s.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	z, r, err := s.findRecord(ctx, name, *id)
+	if err != nil {
+		return err
+	}
+	rf.apply(fs, &r)
+	return s.svc.Records.Change(ctx, z, r)
+}
+
+func recordDelete(ctx context.Context, s *session, args []string) error {
+	fs := flag.NewFlagSet("zone", flag.ContinueOnError)
+	id := fs.Int("id", 0, "`ID` of the record")
+	name, err := s.parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	z, r, err := s.findRecord(ctx, name, *id)
+	if err != nil {
+		return err
+	}
+	return s.svc.Records.Delete(ctx, z, r)
+}
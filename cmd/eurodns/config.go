@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/omines/eurodnsgo"
+	"gopkg.in/yaml.v3"
+)
+
+// config holds the connection settings of the command
+type config struct {
+	Host         string `yaml:"host"`
+	Username     string `yaml:"username"`
+	Password     string `yaml:"password"`
+	UsernameFile string `yaml:"username_file"`
+	PasswordFile string `yaml:"password_file"`
+}
+
+// loadConfig reads the configuration file, when it exists, and applies
+// the environment variables on top of it
+func (a *app) loadConfig(path string) (config, error) {
+	var cfg config
+
+	explicit := path != ""
+	if !explicit {
+		path = a.getenv("EURODNS_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".config", "eurodns", "config.yaml")
+		}
+	}
+
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(b, &cfg); err != nil {
+				return cfg, fmt.Errorf("config %s: %s", path, err)
+			}
+		case explicit || !os.IsNotExist(err):
+			return cfg, err
+		}
+	}
+
+	for _, v := range []struct {
+		name  string
+		value *string
+	}{
+		{"EURODNS_HOST", &cfg.Host},
+		{eurodnsgo.EnvUsername, &cfg.Username},
+		{eurodnsgo.EnvPassword, &cfg.Password},
+	} {
+		if s := a.getenv(v.name); s != "" {
+			*v.value = s
+		}
+	}
+
+	return cfg, nil
+}
+
+// clientConfig returns the client configuration for the settings
+func (cfg config) clientConfig() (eurodnsgo.ClientConfig, error) {
+	cc := eurodnsgo.ClientConfig{
+		Host: cfg.Host,
+	}
+	if cc.Host == "" {
+		return cc, errors.New("no host configured, set EURODNS_HOST or use -host")
+	}
+
+	switch {
+	case cfg.UsernameFile != "" || cfg.PasswordFile != "":
+		cc.Credentials = eurodnsgo.FileCredentials(cfg.UsernameFile, cfg.PasswordFile)
+	case cfg.Username != "" && cfg.Password != "":
+		cc.Credentials = eurodnsgo.StaticCredentials(cfg.Username, cfg.Password)
+	default:
+		return cc, fmt.Errorf("no credentials configured, set %s and %s", eurodnsgo.EnvUsername, eurodnsgo.EnvPassword)
+	}
+	return cc, nil
+}
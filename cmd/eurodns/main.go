@@ -0,0 +1,172 @@
+// Command eurodns manages the domains, zones and records of a EuroDNS
+// account from the command line.
+//
+// Usage:
+//
+//	eurodns [-config file] [-host host] [-o table|json|zone] <command> [arguments]
+//
+// The commands are:
+//
+//	domains list                     list all domains
+//	domain info <domain>             show whether a domain has a zone
+//	zones list                       list all zones
+//	zone show <zone>                 show the records of a zone
+//	zone export <zone> [-format f]   write a zone as zone file, JSON or YAML
+//	zone import <zone> -file f       synchronize a zone with a file
+//	record add <zone> -type t ...    add a record
+//	record change <zone> -id n ...   change a record
+//	record delete <zone> -id n       delete a record
+//
+// Credentials are read from the EURODNS_HOST, EURODNS_USERNAME and
+// EURODNS_PASSWORD environment variables, or from a YAML configuration file:
+//
+//	host: api.eurodns-endpoint.org
+//	username: user
+//	password: secret
+//	# alternatively read the credentials from files
+//	username_file: /run/secrets/eurodns-username
+//	password_file: /run/secrets/eurodns-password
+//
+// The configuration file is read from the -config flag, the EURODNS_CONFIG
+// environment variable or ~/.config/eurodns/config.yaml, in that order.
+// Environment variables take precedence over the configuration file.
+//
+// The command only uses the public api package, so it doubles as an
+// example of its usage.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// Output formats selected by the -o flag
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatZone  = "zone"
+)
+
+// command is a subcommand like "zone show"
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(ctx context.Context, s *session, args []string) error
+}
+
+var commands = []command{
+	{"domains list", "", "list all domains", domainsList},
+	{"domain info", "<domain>", "show whether a domain has a zone", domainInfo},
+	{"zones list", "", "list all zones", zonesList},
+	{"zone show", "<zone>", "show the records of a zone", zoneShow},
+	{"zone export", "<zone> [-format zone|json|yaml] [-file path]", "write a zone as zone file, JSON or YAML", zoneExport},
+	{"zone import", "<zone> -file path [-dry-run]", "synchronize a zone with a file", zoneImport},
+	{"record add", "<zone> -type t [-host h] -data d [-ttl n] [-priority n]", "add a record", recordAdd},
+	{"record change", "<zone> -id n [-host h] [-type t] [-data d] [-ttl n] [-priority n]", "change a record", recordChange},
+	{"record delete", "<zone> -id n", "delete a record", recordDelete},
+}
+
+// app holds the environment of the command, so it can be run by tests
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	// httpClient performs the requests, a default client is used when nil
+	httpClient *http.Client
+}
+
+// session holds the state of a single invocation
+type session struct {
+	*app
+	svc    api.Services
+	format string
+}
+
+func main() {
+	a := &app{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: eurodns [flags] <command> [arguments]")
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(a.stderr, "  %s %s\n    \t%s\n", c.name, c.usage, c.help)
+	}
+	fmt.Fprintln(a.stderr, "\nFlags:")
+	fs.PrintDefaults()
+}
+
+// run executes the command line and returns the exit code
+func (a *app) run(args []string) int {
+	fs := flag.NewFlagSet("eurodns", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	configPath := fs.String("config", "", "configuration `file`, see the package documentation")
+	host := fs.String("host", "", "API `host`, overrides the configuration")
+	format := fs.String("o", formatTable, "output `format`: table, json or zone")
+	fs.Usage = func() { a.usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	args = fs.Args()
+
+	var cmd *command
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == commands[i].name {
+			cmd = &commands[i]
+			args = args[len(words):]
+			break
+		}
+	}
+	if cmd == nil {
+		a.usage(fs)
+		return 2
+	}
+	if *format != formatTable && *format != formatJSON && *format != formatZone {
+		fmt.Fprintf(a.stderr, "eurodns: unknown output format %q\n", *format)
+		return 2
+	}
+
+	cfg, err := a.loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "eurodns: %s\n", err)
+		return 1
+	}
+	if *host != "" {
+		cfg.Host = *host
+	}
+	cc, err := cfg.clientConfig()
+	if err != nil {
+		fmt.Fprintf(a.stderr, "eurodns: %s\n", err)
+		return 1
+	}
+	cc.HTTPClient = a.httpClient
+	cc.Logger = eurodnsgo.NopLogger
+
+	c, err := eurodnsgo.NewClient(cc)
+	if err != nil {
+		fmt.Fprintf(a.stderr, "eurodns: %s\n", err)
+		return 1
+	}
+
+	s := &session{app: a, svc: api.NewServices(c), format: *format}
+	if err := cmd.run(context.Background(), s, args); err != nil {
+		if err == flag.ErrHelp {
+			return 2
+		}
+		fmt.Fprintf(a.stderr, "eurodns %s: %s\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/omines/eurodnsgo/api"
+	"github.com/omines/eurodnsgo/eurodnstest"
+)
+
+func testZone() api.Zone {
+	return api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{Host: "www", Type: api.RecordTypeA, Data: "192.0.2.1", TTL: 3600},
+			{Host: "@", Type: api.RecordTypeMX, Data: "mail.example.org.", Priority: 10, TTL: 3600},
+		},
+	}
+}
+
+// testApp returns an app connecting to srv and its output buffers
+func testApp(srv *eurodnstest.Server) (*app, *bytes.Buffer, *bytes.Buffer) {
+	env := map[string]string{
+		"EURODNS_HOST":     strings.TrimPrefix(srv.URL, "https://"),
+		"EURODNS_USERNAME": srv.Username,
+		"EURODNS_PASSWORD": srv.Password,
+		// keep the configuration file of the user out of the tests
+		"EURODNS_CONFIG": os.DevNull,
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &app{
+		stdout:     stdout,
+		stderr:     stderr,
+		getenv:     func(k string) string { return env[k] },
+		httpClient: srv.Config().HTTPClient,
+	}, stdout, stderr
+}
+
+func TestLists(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddDomain("example.net")
+	srv.AddZone(testZone())
+
+	a, stdout, stderr := testApp(srv)
+	if code := a.run([]string{"domains", "list"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if stdout.String() != "example.net\nexample.org\n" {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	stdout.Reset()
+	if code := a.run([]string{"-o", "json", "zones", "list"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	var zones []string
+	if err := json.Unmarshal(stdout.Bytes(), &zones); err != nil || len(zones) != 1 || zones[0] != "example.org" {
+		t.Errorf("unexpected zones %q, %v", stdout, err)
+	}
+}
+
+func TestDomainInfo(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddDomain("example.net")
+	srv.AddZone(testZone())
+
+	a, stdout, stderr := testApp(srv)
+	if code := a.run([]string{"domain", "info", "example.org"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout.String(), "zone:    true") || !strings.Contains(stdout.String(), "records: 2 (1 A, 1 MX)") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	stdout.Reset()
+	if code := a.run([]string{"domain", "info", "example.net"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout.String(), "managed: true") || !strings.Contains(stdout.String(), "zone:    false") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+}
+
+func TestZoneShow(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddZone(testZone())
+
+	a, stdout, stderr := testApp(srv)
+	if code := a.run([]string{"zone", "show", "example.org"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[2], "mail.example.org.") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	if code := a.run([]string{"zone", "show", "example.com"}); code != 1 {
+		t.Errorf("expected exit code 1 for an unknown zone, got %d", code)
+	}
+}
+
+func TestZoneExportImport(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddZone(testZone())
+
+	dir, err := ioutil.TempDir("", "eurodns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"example.org.zone", "example.org.json", "example.org.yaml"} {
+		path := filepath.Join(dir, name)
+		a, _, stderr := testApp(srv)
+		if code := a.run([]string{"zone", "export", "example.org", "-file", path}); code != 0 {
+			t.Fatalf("%s: exit code %d: %s", name, code, stderr)
+		}
+
+		a, stdout, stderr := testApp(srv)
+		if code := a.run([]string{"zone", "import", "example.org", "-file", path, "-dry-run"}); code != 0 {
+			t.Fatalf("%s: exit code %d: %s", name, code, stderr)
+		}
+		if !strings.HasPrefix(stdout.String(), "zone example.org: 0 to add, 0 to change, 0 to remove") {
+			t.Errorf("%s: expected an empty plan, got %q", name, stdout)
+		}
+	}
+
+	a, stdout, stderr := testApp(srv)
+	if code := a.run([]string{"zone", "import", "example.net", "-file", filepath.Join(dir, "example.org.json"), "-dry-run"}); code != 1 || !strings.Contains(stderr.String(), "not example.net") {
+		t.Errorf("expected an error for a file of another zone, got %d: %s", code, stderr)
+	}
+
+	path := filepath.Join(dir, "desired.zone")
+	if err := ioutil.WriteFile(path, []byte("www 3600 IN A 192.0.2.2\nftp 3600 IN A 192.0.2.3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, stdout, stderr = testApp(srv)
+	if code := a.run([]string{"zone", "import", "example.org", "-file", path}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout.String(), "zone example.org: 1 to add, 1 to change, 1 to remove") {
+		t.Errorf("unexpected plan %q", stdout)
+	}
+	z, _ := srv.Zone("example.org")
+	if len(z.Records) != 2 || z.Find("ftp", api.RecordTypeA) == nil || z.Find("www", api.RecordTypeA).Data != "192.0.2.2" {
+		t.Errorf("expected the zone file to be applied, got %+v", z.Records)
+	}
+}
+
+func TestRecords(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddZone(testZone())
+
+	a, _, stderr := testApp(srv)
+	if code := a.run([]string{"record", "add", "example.org", "-host", "ftp", "-type", "a", "-data", "192.0.2.3", "-ttl", "600"}); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	z, _ := srv.Zone("example.org")
+	ftp := z.Find("ftp", api.RecordTypeA)
+	if ftp == nil || ftp.Data != "192.0.2.3" || ftp.TTL != 600 {
+		t.Fatalf("expected the record to be added, got %+v", z.Records)
+	}
+
+	id := []string{"-id", strconv.Itoa(ftp.ID)}
+	if code := a.run(append([]string{"record", "change", "example.org", "-data", "192.0.2.4"}, id...)); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	z, _ = srv.Zone("example.org")
+	if r := z.Find("ftp", api.RecordTypeA); r == nil || r.Data != "192.0.2.4" || r.TTL != 600 {
+		t.Fatalf("expected only the data to change, got %+v", r)
+	}
+
+	if code := a.run(append([]string{"record", "delete", "example.org"}, id...)); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+	z, _ = srv.Zone("example.org")
+	if z.Find("ftp", api.RecordTypeA) != nil {
+		t.Error("expected the record to be deleted")
+	}
+
+	if code := a.run([]string{"record", "delete", "example.org", "-id", "9999"}); code != 1 {
+		t.Errorf("expected exit code 1 for an unknown record, got %d", code)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+
+	a, _, stderr := testApp(srv)
+	if code := a.run([]string{"zone", "destroy"}); code != 2 || !strings.Contains(stderr.String(), "zone import") {
+		t.Errorf("expected the usage with exit code 2, got %d: %s", code, stderr)
+	}
+
+	a, _, stderr = testApp(srv)
+	if code := a.run([]string{"zone", "show"}); code != 1 || !strings.Contains(stderr.String(), "missing zone name") {
+		t.Errorf("expected a missing zone error, got %d: %s", code, stderr)
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "eurodns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("host: api.example.org\nusername: user\npassword: secret\n")
+	f.Close()
+
+	a := &app{getenv: func(k string) string {
+		if k == "EURODNS_PASSWORD" {
+			return "override"
+		}
+		return ""
+	}}
+	cfg, err := a.loadConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Host != "api.example.org" || cfg.Username != "user" || cfg.Password != "override" {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+
+	if _, err := a.loadConfig(f.Name() + ".missing"); err == nil {
+		t.Error("expected an error for a missing configuration file")
+	}
+	if _, err := (config{Host: "api.example.org"}).clientConfig(); err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
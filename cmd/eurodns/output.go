@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/omines/eurodnsgo/api"
+	"github.com/omines/eurodnsgo/zonefile"
+)
+
+// writeJSON writes v as indented JSON to stdout
+func (s *session) writeJSON(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.stdout, "%s\n", b)
+	return err
+}
+
+// writeNames writes a list of domain or zone names
+func (s *session) writeNames(names []string) error {
+	if s.format == formatJSON {
+		if names == nil {
+			names = []string{}
+		}
+		return s.writeJSON(names)
+	}
+	for _, n := range names {
+		fmt.Fprintln(s.stdout, n)
+	}
+	return nil
+}
+
+// writeZone writes the records of a zone in the selected output format
+func (s *session) writeZone(z api.Zone) error {
+	switch s.format {
+	case formatJSON:
+		return s.writeJSON(z)
+	case formatZone:
+		return zonefile.Write(s.stdout, z)
+	}
+	return s.writeRecords(z.Records)
+}
+
+// writeRecords writes records as a table
+func (s *session) writeRecords(records []*api.Record) error {
+	tw := tabwriter.NewWriter(s.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tHOST\tTYPE\tTTL\tPRIORITY\tDATA")
+	for _, r := range records {
+		priority := ""
+		if r.Type == api.RecordTypeMX || r.Type == api.RecordTypeSRV {
+			priority = fmt.Sprint(r.Priority)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\n", r.ID, host(r.Host), r.Type, r.TTL, priority, r.Data)
+	}
+	return tw.Flush()
+}
+
+// host returns the host of a record, showing the zone apex as @
+func host(h string) string {
+	if h == "" {
+		return "@"
+	}
+	return h
+}
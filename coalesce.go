@@ -0,0 +1,117 @@
+package eurodnsgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// errAbandoned is returned by flight.wait when the request which started
+// the flight was cancelled, so the waiting request has to be performed
+// again instead of failing with the context error of another caller
+var errAbandoned = errors.New("coalesced request abandoned")
+
+// flight is a read request in progress, which identical read requests
+// wait for instead of performing their own call
+type flight struct {
+	key    string
+	done   chan struct{}
+	result []byte
+	err    error
+	// abandoned is set when the call failed because the context of the
+	// request which started the flight ended
+	abandoned bool
+}
+
+// flights tracks the read requests in progress by their content
+type flights struct {
+	mu       sync.Mutex
+	inflight map[string]*flight
+}
+
+// callLane is the lane of the flights of requests performed by Call,
+// scheduled requests use the lane of their Priority
+const callLane = -1
+
+// flightKey returns the key of a read request in a lane. Requests are only
+// shared within a lane, so a request never waits behind a request of a
+// lower priority.
+func flightKey(sr *SoapRequest, lane int) string {
+	return strconv.Itoa(lane) + "\n" + sr.PrepareContent()
+}
+
+// join returns the flight of an identical read request in progress in the
+// lane, or starts a new flight reported by the second return value. Flights
+// are only tracked for read requests, nil is returned for any other
+// request.
+func (fs *flights) join(sr *SoapRequest, lane int) (*flight, bool) {
+	if !sr.ReadOnly() {
+		fs.mu.Lock()
+		// reads started before a write must not be shared with reads
+		// started after it
+		fs.inflight = nil
+		fs.mu.Unlock()
+		return nil, true
+	}
+
+	key := flightKey(sr, lane)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if f, ok := fs.inflight[key]; ok {
+		return f, false
+	}
+
+	if fs.inflight == nil {
+		fs.inflight = make(map[string]*flight)
+	}
+	f := &flight{key: key, done: make(chan struct{})}
+	fs.inflight[key] = f
+	return f, true
+}
+
+// land completes a flight started by join, releasing all waiting requests.
+// The context is the one of the request which started the flight.
+func (fs *flights) land(ctx context.Context, f *flight, result []byte, err error) {
+	if f == nil {
+		return
+	}
+
+	fs.mu.Lock()
+	if fs.inflight[f.key] == f {
+		delete(fs.inflight, f.key)
+	}
+	fs.mu.Unlock()
+
+	f.result, f.err = result, err
+	f.abandoned = err != nil && ctx.Err() != nil
+	close(f.done)
+}
+
+// wait blocks until the flight landed, and decodes its result into the
+// result of the request. It returns errAbandoned when the request has to
+// be performed again.
+func (f *flight) wait(ctx context.Context, sr *SoapRequest) ([]byte, error) {
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if f.abandoned {
+		// a cancelled request is not performed again
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errAbandoned
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := sr.DecodeResult(f.result); err != nil {
+		return nil, err
+	}
+	return f.result, nil
+}
@@ -0,0 +1,170 @@
+package eurodnsgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func coalesceClient(t *testing.T, coalesce bool, calls *int32) (Client, func()) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`<response><result code="1000"><msg>ok</msg></result><resData><zone:name>example.org</zone:name></resData></response>`))
+	}))
+
+	c, err := NewClient(ClientConfig{
+		Host:       strings.TrimPrefix(srv.URL, "https://"),
+		Username:   "username",
+		Password:   "password",
+		HTTPClient: srv.Client(),
+		Logger:     NopLogger,
+		Coalesce:   coalesce,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, srv.Close
+}
+
+// readZone performs a zone:info request and returns the decoded zone name
+func readZone(ctx context.Context, c Client, scheduled bool) (string, error) {
+	var v zoneName
+	sr := NewSoapRequest("zone", "info", &v)
+	sr.AddParam(NewParam("zone", "name", "example.org"))
+	if scheduled {
+		ch, err := c.Schedule(ctx, sr)
+		if err != nil {
+			return "", err
+		}
+		<-ch
+		if err := sr.Err(); err != nil {
+			return "", err
+		}
+	} else if err := c.Call(ctx, sr); err != nil {
+		return "", err
+	}
+	return v.Name, nil
+}
+
+// callConcurrently performs the same read request from several goroutines
+// and returns the decoded zone names
+func callConcurrently(c Client, n int, scheduled bool) []string {
+	names := make([]string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			names[i], _ = readZone(context.TODO(), c, scheduled)
+		}(i)
+	}
+	wg.Wait()
+	return names
+}
+
+func TestCoalescing(t *testing.T) {
+	for _, scheduled := range []bool{false, true} {
+		var calls int32
+		c, done := coalesceClient(t, true, &calls)
+
+		for i, name := range callConcurrently(c, 5, scheduled) {
+			if name != "example.org" {
+				t.Errorf("request %d: expected the shared result to be decoded, got %q", i, name)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("expected a single call, got %d", calls)
+		}
+		done()
+	}
+}
+
+func TestCoalescingDisabled(t *testing.T) {
+	var calls int32
+	c, done := coalesceClient(t, false, &calls)
+	defer done()
+
+	callConcurrently(c, 3, false)
+	if calls != 3 {
+		t.Errorf("expected every request to be performed, got %d calls", calls)
+	}
+}
+
+func TestCoalescingCancelledLeader(t *testing.T) {
+	for _, scheduled := range []bool{false, true} {
+		var calls int32
+		c, done := coalesceClient(t, true, &calls)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := readZone(ctx, c, scheduled)
+			leaderErr <- err
+		}()
+
+		// the followers join the flight of the leader, which is cancelled
+		// while they wait
+		time.Sleep(20 * time.Millisecond)
+		time.AfterFunc(20*time.Millisecond, cancel)
+		for i, name := range callConcurrently(c, 3, scheduled) {
+			if name != "example.org" {
+				t.Errorf("scheduled %v, request %d: expected the request to be performed again, got %q", scheduled, i, name)
+			}
+		}
+		if err := <-leaderErr; err == nil {
+			t.Errorf("scheduled %v: expected the cancelled request to fail", scheduled)
+		}
+		if n := atomic.LoadInt32(&calls); n > 2 {
+			t.Errorf("scheduled %v: expected the followers to share a new call, got %d calls", scheduled, n)
+		}
+		done()
+	}
+}
+
+func TestCoalescingLanes(t *testing.T) {
+	fs := &flights{}
+	read := NewSoapRequest("zone", "info", nil)
+
+	low, _ := fs.join(read, int(PriorityLow))
+	if g, leader := fs.join(read, int(PriorityHigh)); !leader || g == low {
+		t.Error("expected a read of a higher priority not to wait for a lower lane")
+	}
+	if g, leader := fs.join(read, callLane); !leader || g == low {
+		t.Error("expected a direct call not to wait for a scheduled call")
+	}
+	if g, leader := fs.join(read, int(PriorityLow)); leader || g != low {
+		t.Error("expected an identical read in the same lane to join the flight")
+	}
+}
+
+func TestCoalescingWrite(t *testing.T) {
+	fs := &flights{}
+	read := NewSoapRequest("zone", "info", nil)
+
+	f, leader := fs.join(read, callLane)
+	if !leader {
+		t.Fatal("expected the first read to start a flight")
+	}
+	if g, leader := fs.join(read, callLane); leader || g != f {
+		t.Error("expected an identical read to join the flight")
+	}
+
+	if _, leader := fs.join(NewSoapRequest("zone", "update", nil), callLane); !leader {
+		t.Error("expected writes to be performed")
+	}
+	if g, leader := fs.join(read, callLane); !leader || g == f {
+		t.Error("expected reads after a write to start a new flight")
+	}
+
+	// landing the replaced flight does not affect the new one
+	fs.land(context.TODO(), f, nil, nil)
+	if len(fs.inflight) != 1 {
+		t.Errorf("expected the new flight to remain, got %d flights", len(fs.inflight))
+	}
+}
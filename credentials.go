@@ -0,0 +1,135 @@
+package eurodnsgo
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables read by EnvCredentials by default
+const (
+	EnvUsername = "EURODNS_USERNAME"
+	EnvPassword = "EURODNS_PASSWORD"
+)
+
+// Credentials authenticate the requests to the API
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// String returns the username, the password is never printed
+func (c Credentials) String() string {
+	return c.Username + ":REDACTED"
+}
+
+// GoString returns the username, the password is never printed
+func (c Credentials) GoString() string {
+	return fmt.Sprintf("eurodnsgo.Credentials{Username:%q, Password:\"REDACTED\"}", c.Username)
+}
+
+func (c Credentials) validate() error {
+	if len(c.Username) == 0 {
+		return fmt.Errorf("credentials: no username provided")
+	}
+	if len(c.Password) == 0 {
+		return fmt.Errorf("credentials: no password provided")
+	}
+	return nil
+}
+
+// CredentialsProvider is consulted for the credentials of every request,
+// so credentials can be rotated without creating a new client
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc is a function implementing CredentialsProvider
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls the function
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// StaticCredentials returns a CredentialsProvider which always provides the
+// same credentials
+func StaticCredentials(username, password string) CredentialsProvider {
+	c := Credentials{username, password}
+	return CredentialsFunc(func(ctx context.Context) (Credentials, error) {
+		return c, nil
+	})
+}
+
+// EnvCredentials returns a CredentialsProvider reading the credentials from
+// environment variables for every request. Empty names default to
+// EnvUsername and EnvPassword.
+func EnvCredentials(usernameVar, passwordVar string) CredentialsProvider {
+	if usernameVar == "" {
+		usernameVar = EnvUsername
+	}
+	if passwordVar == "" {
+		passwordVar = EnvPassword
+	}
+
+	return CredentialsFunc(func(ctx context.Context) (Credentials, error) {
+		c := Credentials{os.Getenv(usernameVar), os.Getenv(passwordVar)}
+		if err := c.validate(); err != nil {
+			return Credentials{}, fmt.Errorf("%s, set %s and %s", err, usernameVar, passwordVar)
+		}
+		return c, nil
+	})
+}
+
+// FileCredentials returns a CredentialsProvider reading the username and
+// password from two files, like the keys of a mounted Kubernetes secret.
+// The files are read again whenever their modification time changes, and
+// surrounding whitespace is removed from their contents.
+func FileCredentials(usernameFile, passwordFile string) CredentialsProvider {
+	return &fileCredentials{files: [2]string{usernameFile, passwordFile}}
+}
+
+type fileCredentials struct {
+	files [2]string
+
+	mu       sync.Mutex
+	modified [2]time.Time
+	c        Credentials
+}
+
+func (f *fileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var modified [2]time.Time
+	for i, name := range f.files {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("credentials: %s", err)
+		}
+		modified[i] = fi.ModTime()
+	}
+	if modified == f.modified {
+		return f.c, nil
+	}
+
+	var values [2]string
+	for i, name := range f.files {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("credentials: %s", err)
+		}
+		values[i] = strings.TrimSpace(string(b))
+	}
+
+	c := Credentials{values[0], values[1]}
+	if err := c.validate(); err != nil {
+		return Credentials{}, err
+	}
+	f.c, f.modified = c, modified
+	return c, nil
+}
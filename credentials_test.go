@@ -0,0 +1,105 @@
+package eurodnsgo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCredentialsString(t *testing.T) {
+	c := Credentials{"user", "secret"}
+	for _, s := range []string{fmt.Sprint(c), fmt.Sprintf("%+v", c), fmt.Sprintf("%#v", c)} {
+		if strings.Contains(s, "secret") || !strings.Contains(s, "user") {
+			t.Errorf("expected the password to be redacted, got %s", s)
+		}
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	defer os.Unsetenv("EURODNS_TEST_USERNAME")
+	defer os.Unsetenv("EURODNS_TEST_PASSWORD")
+
+	p := EnvCredentials("EURODNS_TEST_USERNAME", "EURODNS_TEST_PASSWORD")
+	if _, err := p.Credentials(context.TODO()); err == nil {
+		t.Error("expected an error for unset variables")
+	}
+
+	os.Setenv("EURODNS_TEST_USERNAME", "user")
+	os.Setenv("EURODNS_TEST_PASSWORD", "secret")
+	if c, err := p.Credentials(context.TODO()); err != nil || c.Username != "user" || c.Password != "secret" {
+		t.Errorf("unexpected credentials %v: %v", c, err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eurodnsgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	username := filepath.Join(dir, "username")
+	password := filepath.Join(dir, "password")
+	write := func(name, content string, modified time.Time) {
+		if err := ioutil.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(name, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := FileCredentials(username, password)
+	if _, err := p.Credentials(context.TODO()); err == nil {
+		t.Error("expected an error for missing files")
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write(username, "user\n", start)
+	write(password, "secret\n", start)
+	if c, err := p.Credentials(context.TODO()); err != nil || c.Username != "user" || c.Password != "secret" {
+		t.Errorf("unexpected credentials %v: %v", c, err)
+	}
+
+	write(password, "rotated\n", start.Add(time.Minute))
+	if c, err := p.Credentials(context.TODO()); err != nil || c.Password != "rotated" {
+		t.Errorf("expected the rotated password, got %v: %v", c, err)
+	}
+}
+
+func TestCredentialsProvider(t *testing.T) {
+	var header http.Header
+	srv := resultServer(1000, &header)
+	defer srv.Close()
+
+	password := "first"
+	c, err := NewClient(ClientConfig{
+		Host: strings.TrimPrefix(srv.URL, "https://"),
+		Credentials: CredentialsFunc(func(ctx context.Context) (Credentials, error) {
+			return Credentials{"user", password}, nil
+		}),
+		HTTPClient: srv.Client(),
+		Logger:     NopLogger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"first", "second"} {
+		password = p
+		if err := c.Call(context.TODO(), NewSoapRequest("zone", "update", nil)); err != nil {
+			t.Fatal(err)
+		}
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:"+p))
+		if auth := header.Get("Authorization"); auth != expected {
+			t.Errorf("expected %q, got %q", expected, auth)
+		}
+	}
+}
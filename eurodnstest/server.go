@@ -0,0 +1,380 @@
+// Package eurodnstest provides an in-process fake of the EuroDNS API for
+// tests of code using eurodnsgo.Client.
+//
+// The Server understands the form encoded envelopes sent by the client,
+// keeps domains, zones and records in memory and answers with the same
+// response documents as the real API. Faults like error result codes and
+// latency can be injected to exercise error handling.
+//
+//	srv := eurodnstest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddZone(api.Zone{Name: "example.org"})
+//	zones, err := api.GetZoneList(ctx, srv.Client())
+package eurodnstest
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// Credentials accepted by a Server unless configured otherwise
+const (
+	Username = "test"
+	Password = "secret"
+)
+
+// Result codes sent by a Server
+const (
+	CodeSuccess       = 1000
+	CodeSyntaxError   = 2001
+	CodeUnimplemented = 2101
+	CodeAuthError     = 2200
+	CodeObjectExists  = 2302
+	CodeNotFound      = 2303
+	CodeFailed        = 2400
+)
+
+// Fault describes an injected failure for matching requests
+type Fault struct {
+	// Namespace and Method select the requests the fault applies to, an
+	// empty value matches any request
+	Namespace string
+	Method    string
+	// Code is the result code sent instead of processing the request, no
+	// error is sent when it is 0
+	Code    int
+	Message string
+	// Latency delays the response
+	Latency time.Duration
+	// Times limits how often the fault applies, 0 applies it forever
+	Times int
+}
+
+func (f *Fault) matches(namespace, method string) bool {
+	return (f.Namespace == "" || f.Namespace == namespace) && (f.Method == "" || f.Method == method)
+}
+
+// Request is a request received by a Server
+type Request struct {
+	Namespace string
+	Method    string
+	// Body holds the unescaped XML document
+	Body string
+}
+
+// Server is a fake EuroDNS API server
+type Server struct {
+	// URL of the server, in the form https://ipaddr:port
+	URL string
+	// Username and Password are the accepted credentials
+	Username string
+	Password string
+
+	srv *httptest.Server
+
+	clientOnce sync.Once
+	client     eurodnsgo.Client
+
+	mu       sync.Mutex
+	domains  map[string]bool
+	zones    map[string]*api.Zone
+	nextID   int
+	faults   []*Fault
+	requests []Request
+}
+
+// NewServer starts and returns a new Server with empty state. The caller
+// should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		Username: Username,
+		Password: Password,
+		domains:  make(map[string]bool),
+		zones:    make(map[string]*api.Zone),
+		nextID:   1,
+	}
+	s.srv = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns a client configuration connecting to the server
+func (s *Server) Config() eurodnsgo.ClientConfig {
+	return eurodnsgo.ClientConfig{
+		Host:       strings.TrimPrefix(s.URL, "https://"),
+		Username:   s.Username,
+		Password:   s.Password,
+		HTTPClient: s.srv.Client(),
+	}
+}
+
+// Client returns the client connecting to the server. It is created on
+// first use and shared by all callers, as every client runs a scheduler
+// goroutine which cannot be stopped. Use Config to create a client with
+// different settings.
+func (s *Server) Client() eurodnsgo.Client {
+	s.clientOnce.Do(func() {
+		// the configuration is always complete, so no error can occur
+		s.client, _ = eurodnsgo.NewClient(s.Config())
+	})
+	return s.client
+}
+
+// AddDomain registers domains returned by domain:list
+func (s *Server) AddDomain(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, name := range names {
+		s.domains[name] = true
+	}
+}
+
+// AddZone stores a zone, replacing any zone with the same name. Records
+// without an ID are assigned one, the zone is registered as domain too.
+func (s *Server) AddZone(z api.Zone) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := &api.Zone{Name: z.Name}
+	for _, r := range z.Records {
+		stored.Records = append(stored.Records, s.newRecord(*r))
+	}
+	s.zones[z.Name] = stored
+	s.domains[z.Name] = true
+}
+
+// newRecord returns a copy of the record with an ID assigned when missing
+func (s *Server) newRecord(r api.Record) *api.Record {
+	if r.ID == 0 {
+		r.ID = s.nextID
+	}
+	if r.ID >= s.nextID {
+		s.nextID = r.ID + 1
+	}
+	return &r
+}
+
+// Zone returns a copy of the current state of a zone
+func (s *Server) Zone(name string) (api.Zone, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	z, ok := s.zones[name]
+	if !ok {
+		return api.Zone{}, false
+	}
+	return copyZone(z), true
+}
+
+func copyZone(z *api.Zone) api.Zone {
+	c := api.Zone{Name: z.Name}
+	for _, r := range z.Records {
+		rr := *r
+		c.Records = append(c.Records, &rr)
+	}
+	return c
+}
+
+// Inject adds a fault for matching requests. Faults are checked in the
+// order they were injected, the first matching fault applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns all requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// fault returns the fault applying to the request, if any
+func (s *Server) fault(namespace, method string) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(namespace, method) {
+			continue
+		}
+		applied := *f
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &applied
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != s.Username || pass != s.Password {
+		writeResponse(w, "", CodeAuthError, "Authentication error", nil)
+		return
+	}
+
+	// the client escapes the envelope twice, the form parsing undoes once
+	body, err := url.QueryUnescape(r.PostFormValue("xml"))
+	if err != nil {
+		writeResponse(w, "", CodeSyntaxError, "Command syntax error", nil)
+		return
+	}
+	var req request
+	if err := xml.Unmarshal([]byte(body), &req); err != nil {
+		writeResponse(w, "", CodeSyntaxError, "Command syntax error", nil)
+		return
+	}
+	namespace, method := req.namespace(), req.Op.XMLName.Local
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{namespace, method, body})
+	f := s.fault(namespace, method)
+	s.mu.Unlock()
+
+	if f != nil {
+		if err := sleep(r.Context(), f.Latency); err != nil {
+			return
+		}
+		if f.Code != 0 {
+			writeResponse(w, namespace, f.Code, f.Message, nil)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	data, code, msg := s.handle(namespace, method, req.Op)
+	s.mu.Unlock()
+
+	writeResponse(w, namespace, code, msg, data)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// handle performs a request on the state, it must be called with the
+// mutex held
+func (s *Server) handle(namespace, method string, op operation) ([]byte, int, string) {
+	switch namespace + ":" + method {
+	case "domain:list":
+		return encodeDomainList(sortedKeys(s.domains)), CodeSuccess, ""
+	case "zone:list":
+		names := make(map[string]bool, len(s.zones))
+		for name := range s.zones {
+			names[name] = true
+		}
+		return encodeZoneList(sortedKeys(names)), CodeSuccess, ""
+	case "zone:info":
+		z, ok := s.zones[op.Name]
+		if !ok {
+			return nil, CodeNotFound, "Object does not exist"
+		}
+		return encodeZone(z), CodeSuccess, ""
+	case "zone:update":
+		return s.update(op)
+	case "record:info":
+		for _, z := range s.zones {
+			for _, r := range z.Records {
+				if r.ID == op.ID {
+					return encodeRecord(r), CodeSuccess, ""
+				}
+			}
+		}
+		return nil, CodeNotFound, "Object does not exist"
+	}
+	return nil, CodeUnimplemented, "Unimplemented command"
+}
+
+// update performs a zone:update, which is applied entirely or not at all
+func (s *Server) update(op operation) ([]byte, int, string) {
+	z, ok := s.zones[op.Name]
+	if !ok {
+		return nil, CodeNotFound, "Object does not exist"
+	}
+
+	updated := copyZone(z)
+	find := func(id int) int {
+		for i, r := range updated.Records {
+			if r.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, r := range op.Records.Remove {
+		i := find(r.ID)
+		if i == -1 {
+			return nil, CodeNotFound, "Object does not exist"
+		}
+		updated.Records = append(updated.Records[:i], updated.Records[i+1:]...)
+	}
+	for _, r := range op.Records.Change {
+		i := find(r.ID)
+		if i == -1 {
+			return nil, CodeNotFound, "Object does not exist"
+		}
+		r.apply(updated.Records[i])
+	}
+
+	// IDs are only assigned once the update is known to succeed
+	nextID := s.nextID
+	for _, r := range op.Records.Add {
+		if r.Type == nil || r.Host == nil {
+			return nil, CodeSyntaxError, "Command syntax error"
+		}
+		var rr api.Record
+		r.apply(&rr)
+		for _, existing := range updated.Records {
+			if existing.Type == rr.Type && existing.Host == rr.Host && existing.Data == rr.Data {
+				return nil, CodeObjectExists, "Object exists"
+			}
+		}
+		rr.ID = nextID
+		nextID++
+		updated.Records = append(updated.Records, &rr)
+	}
+
+	s.nextID = nextID
+	s.zones[op.Name] = &updated
+	return nil, CodeSuccess, ""
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
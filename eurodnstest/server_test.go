@@ -0,0 +1,223 @@
+package eurodnstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+func testZone() api.Zone {
+	return api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{Host: "@", Type: api.RecordTypeSOA, Data: "ns1.eurodns.com.", RespPerson: "hostmaster.example.org.", Refresh: 3600, Retry: 600, Expire: 1209600, TTL: 3600},
+			{Host: "www", Type: api.RecordTypeA, Data: "192.0.2.1", TTL: 3600},
+			{Host: "@", Type: api.RecordTypeMX, Data: "mail.example.org.", Priority: 10, TTL: 3600},
+		},
+	}
+}
+
+func TestLists(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.TODO()
+
+	srv.AddDomain("example.net")
+	srv.AddZone(testZone())
+
+	domains, err := api.GetDomainList(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(domains) != 2 || domains[0] != "example.net" || domains[1] != "example.org" {
+		t.Errorf("unexpected domains %v", domains)
+	}
+
+	zones, err := api.GetZoneList(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0] != "example.org" {
+		t.Errorf("unexpected zones %v", zones)
+	}
+}
+
+func TestZoneInfo(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.TODO()
+
+	srv.AddZone(testZone())
+
+	z, err := api.GetZoneInfo(ctx, c, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.Name != "example.org" || len(z.Records) != 3 {
+		t.Fatalf("unexpected zone %+v", z)
+	}
+	mx := z.Find("@", api.RecordTypeMX)
+	if mx == nil || mx.ID == 0 || mx.Priority != 10 || mx.Data != "mail.example.org." {
+		t.Errorf("unexpected MX record %+v", mx)
+	}
+
+	soa, err := api.GetSOA(ctx, c, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if soa.RespPerson != "hostmaster.example.org." || soa.Expire != 1209600 {
+		t.Errorf("unexpected SOA %+v", soa)
+	}
+
+	r, err := api.GetRecordInfo(ctx, c, mx.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Data != mx.Data {
+		t.Errorf("unexpected record %+v", r)
+	}
+
+	_, err = api.GetZoneInfo(ctx, c, "example.com")
+	if e, ok := err.(*eurodnsgo.Error); !ok || e.Code != CodeNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestZoneUpdate(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.TODO()
+
+	srv.AddZone(testZone())
+	z, _ := srv.Zone("example.org")
+
+	if err := api.ZoneRecordAdd(ctx, c, z, api.Record{Host: "ftp", Type: api.RecordTypeCNAME, Data: "www", TTL: 3600}); err != nil {
+		t.Fatal(err)
+	}
+
+	www := *z.Find("www", api.RecordTypeA)
+	www.Data = "192.0.2.2"
+	if err := api.ZoneRecordChange(ctx, c, z, www); err != nil {
+		t.Fatal(err)
+	}
+
+	refresh := 7200
+	if err := api.UpdateSOA(ctx, c, z, api.SOAUpdate{Refresh: &refresh}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := api.ZoneRecordDelete(ctx, c, z, *z.Find("@", api.RecordTypeMX)); err != nil {
+		t.Fatal(err)
+	}
+
+	z, _ = srv.Zone("example.org")
+	if len(z.Records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(z.Records))
+	}
+	if r := z.Find("ftp", api.RecordTypeCNAME); r == nil || r.ID == 0 {
+		t.Errorf("expected the added record with an ID, got %+v", r)
+	}
+	if r := z.Find("www", api.RecordTypeA); r.Data != "192.0.2.2" || r.TTL != 3600 {
+		t.Errorf("unexpected changed record %+v", r)
+	}
+	if r := z.Find("@", api.RecordTypeSOA); r.Refresh != 7200 || r.Retry != 600 {
+		t.Errorf("expected only the refresh to change, got %+v", r)
+	}
+
+	err := api.ZoneRecordDelete(ctx, c, z, api.Record{ID: 999})
+	if e, ok := err.(*eurodnsgo.Error); !ok || e.Code != CodeNotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.TODO()
+
+	srv.AddZone(testZone())
+	desired := testZone()
+	desired.Records[1].Data = "192.0.2.10"
+	desired.Records = append(desired.Records, &api.Record{Host: "www", Type: api.RecordTypeAAAA, Data: "2001:db8::1", TTL: 3600})
+
+	p, err := api.PlanZone(ctx, c, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ApplyPlan(ctx, c, p); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = api.PlanZone(ctx, c, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Empty() {
+		t.Errorf("expected the zone to be in sync, got\n%s", p)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.TODO()
+
+	srv.AddZone(testZone())
+	srv.Inject(Fault{Namespace: "zone", Method: "list", Code: CodeFailed, Message: "Command failed", Times: 1})
+
+	_, err := api.GetZoneList(ctx, c)
+	if e, ok := err.(*eurodnsgo.Error); !ok || e.Code != CodeFailed || e.Message != "Command failed" {
+		t.Errorf("expected the injected error, got %v", err)
+	}
+	if _, err := api.GetZoneList(ctx, c); err != nil {
+		t.Errorf("expected the fault to apply once, got %v", err)
+	}
+	if _, err := api.GetDomainList(ctx, c); err != nil {
+		t.Errorf("expected other methods to succeed, got %v", err)
+	}
+
+	srv.Inject(Fault{Latency: time.Second})
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := c.Call(short, eurodnsgo.NewSoapRequest("zone", "list", nil)); err == nil {
+		t.Error("expected the delayed request to time out")
+	}
+
+	if n := len(srv.Requests()); n != 4 {
+		t.Errorf("expected 4 recorded requests, got %d", n)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	cc := srv.Config()
+	cc.Password = "wrong"
+	c, err := eurodnsgo.NewClient(cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = api.GetZoneList(context.TODO(), c)
+	if e, ok := err.(*eurodnsgo.Error); !ok || e.Code != CodeAuthError {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+}
+
+func TestClientShared(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	if srv.Client() != srv.Client() {
+		t.Error("expected a single client per server")
+	}
+}
@@ -0,0 +1,166 @@
+package eurodnstest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/omines/eurodnsgo/api"
+)
+
+// namespaceURL prefixes the namespaces declared in request documents
+const namespaceURL = "http://www.eurodns.com/"
+
+// request is a request document, elements are matched by their local
+// name as the record prefix is never declared by the client
+type request struct {
+	XMLName xml.Name  `xml:"request"`
+	Op      operation `xml:",any"`
+}
+
+func (r request) namespace() string {
+	return strings.TrimPrefix(r.Op.XMLName.Space, namespaceURL)
+}
+
+// operation holds the parameters of all supported methods
+type operation struct {
+	XMLName xml.Name
+	Name    string `xml:"name"`
+	ID      int    `xml:"id"`
+	Records struct {
+		Add    []recordParam `xml:"add>record"`
+		Change []recordParam `xml:"change>record"`
+		Remove []recordParam `xml:"remove>record"`
+	} `xml:"records"`
+}
+
+// recordParam is a record inside a zone:update, fields which are not sent
+// are left untouched by changes
+type recordParam struct {
+	ID         int     `xml:"id,attr"`
+	Data       *string `xml:"data"`
+	Expire     *int    `xml:"expire"`
+	Host       *string `xml:"host"`
+	Priority   *int    `xml:"priority"`
+	Refresh    *int    `xml:"refresh"`
+	RespPerson *string `xml:"resp_person"`
+	Retry      *int    `xml:"retry"`
+	TTL        *int    `xml:"ttl"`
+	Type       *string `xml:"type"`
+}
+
+func (p recordParam) apply(r *api.Record) {
+	if p.Data != nil {
+		r.Data = *p.Data
+	}
+	if p.Expire != nil {
+		r.Expire = *p.Expire
+	}
+	if p.Host != nil {
+		r.Host = *p.Host
+	}
+	if p.Priority != nil {
+		r.Priority = *p.Priority
+	}
+	if p.Refresh != nil {
+		r.Refresh = *p.Refresh
+	}
+	if p.RespPerson != nil {
+		r.RespPerson = *p.RespPerson
+	}
+	if p.Retry != nil {
+		r.Retry = *p.Retry
+	}
+	if p.TTL != nil {
+		r.TTL = *p.TTL
+	}
+	if p.Type != nil {
+		r.Type = api.RecordType(*p.Type)
+	}
+}
+
+func writeResponse(w http.ResponseWriter, namespace string, code int, msg string, data []byte) {
+	var buf bytes.Buffer
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	if namespace == "" {
+		buf.WriteString("<response>")
+	} else {
+		fmt.Fprintf(&buf, `<response xmlns:%s="%s%s">`, namespace, namespaceURL, namespace)
+	}
+	if msg == "" {
+		msg = "Command completed successfully"
+	}
+	fmt.Fprintf(&buf, `<result code="%d">`, code)
+	element(&buf, "msg", msg)
+	buf.WriteString("</result>")
+	if data != nil {
+		buf.WriteString("<resData>")
+		buf.Write(data)
+		buf.WriteString("</resData>")
+	}
+	buf.WriteString("</response>\n")
+
+	w.Write(buf.Bytes())
+}
+
+func element(buf *bytes.Buffer, name string, value interface{}) {
+	fmt.Fprintf(buf, "<%s>", name)
+	xml.EscapeText(buf, []byte(fmt.Sprint(value)))
+	fmt.Fprintf(buf, "</%s>", name)
+}
+
+func encodeDomainList(domains []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<domain:list>")
+	for _, d := range domains {
+		element(&buf, "domain:name", d)
+	}
+	buf.WriteString("</domain:list>")
+	return buf.Bytes()
+}
+
+func encodeZoneList(zones []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<zone:list>")
+	for _, z := range zones {
+		element(&buf, "zone:name", z)
+	}
+	buf.WriteString("</zone:list>")
+	return buf.Bytes()
+}
+
+func encodeZone(z *api.Zone) []byte {
+	var buf bytes.Buffer
+	element(&buf, "zone:name", z.Name)
+	buf.WriteString("<zone:records>")
+	for _, r := range z.Records {
+		fmt.Fprintf(&buf, `<zone:record id="%d">`, r.ID)
+		buf.Write(encodeRecord(r))
+		buf.WriteString("</zone:record>")
+	}
+	buf.WriteString("</zone:records>")
+	return buf.Bytes()
+}
+
+// encodeRecord returns the record fields, SOA properties are only sent for
+// SOA records and priorities for MX and SRV records
+func encodeRecord(r *api.Record) []byte {
+	var buf bytes.Buffer
+	element(&buf, "record:type", r.Type)
+	element(&buf, "record:host", r.Host)
+	element(&buf, "record:data", r.Data)
+	element(&buf, "record:ttl", r.TTL)
+	switch r.Type {
+	case api.RecordTypeMX, api.RecordTypeSRV:
+		element(&buf, "record:priority", r.Priority)
+	case api.RecordTypeSOA:
+		element(&buf, "record:resp_person", r.RespPerson)
+		element(&buf, "record:refresh", r.Refresh)
+		element(&buf, "record:retry", r.Retry)
+		element(&buf, "record:expire", r.Expire)
+	}
+	return buf.Bytes()
+}
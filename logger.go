@@ -0,0 +1,146 @@
+package eurodnsgo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Logger receives structured log entries as a message followed by
+// alternating keys and values. The method set matches *slog.Logger, so one
+// can be set directly as Logger in the ClientConfig.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NopLogger discards all entries
+var NopLogger Logger = nopLogger{}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Warn(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+// NewStdLogger returns a Logger writing entries as key=value lines to the
+// log.Logger, or to the standard logger of the log package when it is nil.
+// Debug entries are discarded unless debug is set.
+func NewStdLogger(l *log.Logger, debug bool) Logger {
+	return &stdLogger{l, debug}
+}
+
+type stdLogger struct {
+	l     *log.Logger
+	debug bool
+}
+
+func (s *stdLogger) Debug(msg string, args ...interface{}) {
+	if s.debug {
+		s.output("DEBUG", msg, args)
+	}
+}
+
+func (s *stdLogger) Info(msg string, args ...interface{}) {
+	s.output("INFO", msg, args)
+}
+
+func (s *stdLogger) Warn(msg string, args ...interface{}) {
+	s.output("WARN", msg, args)
+}
+
+func (s *stdLogger) Error(msg string, args ...interface{}) {
+	s.output("ERROR", msg, args)
+}
+
+func (s *stdLogger) output(level, msg string, args []interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%s", level, logValue(msg))
+	for i := 0; i < len(args); i += 2 {
+		if i+1 == len(args) {
+			fmt.Fprintf(&b, " !BADKEY=%s", logValue(args[i]))
+			break
+		}
+		fmt.Fprintf(&b, " %v=%s", args[i], logValue(args[i+1]))
+	}
+
+	if s.l == nil {
+		log.Print(b.String())
+		return
+	}
+	s.l.Print(b.String())
+}
+
+// logValue formats a value, quoting it when it contains spaces or quotes
+func logValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// redactedHeaders are never logged
+var redactedHeaders = []string{"Authorization", "Cookie"}
+
+func redactHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = v
+	}
+	for _, k := range redactedHeaders {
+		if c.Get(k) != "" {
+			c.Set(k, "REDACTED")
+		}
+	}
+	return c
+}
+
+// logging returns the innermost middleware of a client, logging every
+// call as it is sent. Request and response bodies are only logged at debug
+// level, credentials are never logged.
+func logging(l Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Exchange) error {
+			start := time.Now()
+			err := next(ctx, e)
+			duration := time.Since(start)
+
+			if e.HTTPRequest != nil {
+				l.Debug("eurodns request",
+					"namespace", e.Request.Namespace,
+					"method", e.Request.Method,
+					"header", redactHeader(e.HTTPRequest.Header),
+					"body", e.Envelope)
+			}
+			if e.Body != nil {
+				l.Debug("eurodns response",
+					"namespace", e.Request.Namespace,
+					"method", e.Request.Method,
+					"body", string(e.Body))
+			}
+
+			if err != nil {
+				l.Warn("eurodns call failed",
+					"namespace", e.Request.Namespace,
+					"method", e.Request.Method,
+					"code", e.Code,
+					"duration", duration,
+					"error", err)
+				return err
+			}
+			l.Debug("eurodns call completed",
+				"namespace", e.Request.Namespace,
+				"method", e.Request.Method,
+				"code", e.Code,
+				"duration", duration)
+			return nil
+		}
+	}
+}
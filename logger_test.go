@@ -0,0 +1,79 @@
+package eurodnsgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// resultServer answers all requests with the result code, keeping the
+// headers of the last request when header is not nil
+func resultServer(code int, header *http.Header) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header != nil {
+			*header = r.Header
+		}
+		w.Write([]byte(`<response><result code="` + strconv.Itoa(code) + `"><msg>message</msg></result></response>`))
+	}))
+}
+
+// testLogger keeps all entries as formatted lines
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) add(level, msg string, args []interface{}) {
+	l.lines = append(l.lines, fmt.Sprint(level, " ", msg, " ", args))
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.add("DEBUG", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.add("INFO", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.add("WARN", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.add("ERROR", msg, args) }
+
+func TestLogging(t *testing.T) {
+	srv := resultServer(2303, nil)
+	defer srv.Close()
+
+	l := &testLogger{}
+	c, _ := NewClient(ClientConfig{
+		Host:       strings.TrimPrefix(srv.URL, "https://"),
+		Username:   "username",
+		Password:   "password",
+		HTTPClient: srv.Client(),
+		Logger:     l,
+	})
+	c.Call(context.TODO(), NewSoapRequest("zone", "info", nil))
+
+	if len(l.lines) != 3 {
+		t.Fatalf("expected 3 entries, got %d:\n%s", len(l.lines), strings.Join(l.lines, "\n"))
+	}
+	if !strings.HasPrefix(l.lines[0], "DEBUG eurodns request") || !strings.Contains(l.lines[0], "<zone:info>") {
+		t.Errorf("expected the request body at debug level, got %s", l.lines[0])
+	}
+	if strings.Contains(l.lines[0], "Basic") || !strings.Contains(l.lines[0], "REDACTED") {
+		t.Errorf("expected the credentials to be redacted, got %s", l.lines[0])
+	}
+	if !strings.HasPrefix(l.lines[2], "WARN eurodns call failed [namespace zone method info code 2303") {
+		t.Errorf("expected the failure as warning, got %s", l.lines[2])
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(log.New(&buf, "", 0), false)
+
+	l.Debug("hidden")
+	l.Warn("call failed", "method", "info", "error", "Object does not exist", "odd")
+
+	expected := `level=WARN msg="call failed" method=info error="Object does not exist" !BADKEY=odd` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
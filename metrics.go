@@ -0,0 +1,43 @@
+package eurodnsgo
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics receives measurements of the calls and the scheduling queue of a
+// client. The metrics package provides an implementation exposing them in
+// the Prometheus text format.
+//
+// There is no measurement of retries, as the client never retries a call.
+// Middleware retrying calls reaches ObserveCall once per attempt when it is
+// placed before the built-in middleware, so attempts are counted as calls.
+type Metrics interface {
+	// ObserveCall is called after every call, the code is the result code
+	// of the response document or 0 when no document was received
+	ObserveCall(namespace, method string, code int, duration time.Duration)
+	// SetQueueDepth is called with the number of waiting scheduled calls
+	// whenever a call enters or leaves the queue
+	SetQueueDepth(depth int)
+	// ObserveQueueWait is called with the time a scheduled call waited in
+	// the queue before it was sent
+	ObserveQueueWait(wait time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCall(namespace, method string, code int, duration time.Duration) {}
+func (nopMetrics) SetQueueDepth(depth int)                                                {}
+func (nopMetrics) ObserveQueueWait(wait time.Duration)                                    {}
+
+// measuring returns the middleware reporting every call to the metrics
+func measuring(m Metrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Exchange) error {
+			start := time.Now()
+			err := next(ctx, e)
+			m.ObserveCall(e.Request.Namespace, e.Request.Method, e.Code, time.Since(start))
+			return err
+		}
+	}
+}
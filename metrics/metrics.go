@@ -0,0 +1,211 @@
+// Package metrics implements eurodnsgo.Metrics, collecting the calls of a
+// client and the state of its scheduling queue. The Collector serves the
+// collected metrics in the Prometheus text exposition format, without
+// depending on the Prometheus client library:
+//
+//	m := metrics.New()
+//	c, err := eurodnsgo.NewClient(eurodnsgo.ClientConfig{
+//		// ...
+//		Metrics: m,
+//	})
+//	http.Handle("/metrics", m)
+//
+// The following metrics are exposed:
+//
+//	eurodns_calls_total{namespace,method,code}         counter
+//	eurodns_call_duration_seconds{namespace,method}    histogram
+//	eurodns_queue_depth                                gauge
+//	eurodns_queue_wait_seconds                         histogram
+//
+// A code of 0 counts calls which did not receive a response document.
+//
+// The Collector is not a prometheus.Collector, to keep the Prometheus
+// client library out of the dependencies. Applications using that library
+// can implement eurodnsgo.Metrics with their own counters and histograms,
+// it only has three methods. There is no retry counter, as the client does
+// not retry calls.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the histograms
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Collector implements eurodnsgo.Metrics, it is safe for concurrent use
+type Collector struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	calls     map[callKey]uint64
+	durations map[methodKey]*histogram
+	depth     int
+	wait      *histogram
+}
+
+type methodKey struct {
+	namespace, method string
+}
+
+type callKey struct {
+	methodKey
+	code int
+}
+
+// New returns a Collector using the DefaultBuckets
+func New() *Collector {
+	return NewWithBuckets(DefaultBuckets)
+}
+
+// NewWithBuckets returns a Collector using the given histogram buckets,
+// which must be sorted in increasing order
+func NewWithBuckets(buckets []float64) *Collector {
+	return &Collector{
+		buckets:   buckets,
+		calls:     make(map[callKey]uint64),
+		durations: make(map[methodKey]*histogram),
+		wait:      newHistogram(buckets),
+	}
+}
+
+// ObserveCall counts the call and records its duration
+func (c *Collector) ObserveCall(namespace, method string, code int, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	k := methodKey{namespace, method}
+	c.calls[callKey{k, code}]++
+	h, ok := c.durations[k]
+	if !ok {
+		h = newHistogram(c.buckets)
+		c.durations[k] = h
+	}
+	h.observe(duration.Seconds())
+}
+
+// SetQueueDepth records the number of waiting scheduled calls
+func (c *Collector) SetQueueDepth(depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.depth = depth
+}
+
+// ObserveQueueWait records the time a scheduled call waited
+func (c *Collector) ObserveQueueWait(wait time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wait.observe(wait.Seconds())
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP eurodns_calls_total Calls to the EuroDNS API by result code.\n")
+	b.WriteString("# TYPE eurodns_calls_total counter\n")
+	calls := make([]callKey, 0, len(c.calls))
+	for k := range c.calls {
+		calls = append(calls, k)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].methodKey != calls[j].methodKey {
+			return calls[i].methodKey.less(calls[j].methodKey)
+		}
+		return calls[i].code < calls[j].code
+	})
+	for _, k := range calls {
+		fmt.Fprintf(&b, "eurodns_calls_total{%s,code=\"%d\"} %d\n", k.labels(), k.code, c.calls[k])
+	}
+
+	b.WriteString("# HELP eurodns_call_duration_seconds Duration of calls to the EuroDNS API.\n")
+	b.WriteString("# TYPE eurodns_call_duration_seconds histogram\n")
+	methods := make([]methodKey, 0, len(c.durations))
+	for k := range c.durations {
+		methods = append(methods, k)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].less(methods[j])
+	})
+	for _, k := range methods {
+		c.durations[k].write(&b, "eurodns_call_duration_seconds", k.labels())
+	}
+
+	b.WriteString("# HELP eurodns_queue_depth Scheduled calls waiting to be sent.\n")
+	b.WriteString("# TYPE eurodns_queue_depth gauge\n")
+	fmt.Fprintf(&b, "eurodns_queue_depth %d\n", c.depth)
+
+	b.WriteString("# HELP eurodns_queue_wait_seconds Time scheduled calls waited to be sent.\n")
+	b.WriteString("# TYPE eurodns_queue_wait_seconds histogram\n")
+	c.wait.write(&b, "eurodns_queue_wait_seconds", "")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (k methodKey) less(o methodKey) bool {
+	if k.namespace != o.namespace {
+		return k.namespace < o.namespace
+	}
+	return k.method < o.method
+}
+
+func (k methodKey) labels() string {
+	return fmt.Sprintf("namespace=%q,method=%q", k.namespace, k.method)
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// write writes the cumulative buckets, sum and count of the histogram
+func (h *histogram) write(b *strings.Builder, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	for i, upper := range h.buckets {
+		le := strconv.FormatFloat(upper, 'g', -1, 64)
+		fmt.Fprintf(b, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, sep, le, h.counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, h.count)
+
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, labels, h.count)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+	"github.com/omines/eurodnsgo/eurodnstest"
+)
+
+func TestWriteTo(t *testing.T) {
+	c := NewWithBuckets([]float64{0.1, 1})
+	c.ObserveCall("zone", "info", 1000, 50*time.Millisecond)
+	c.ObserveCall("zone", "info", 1000, 500*time.Millisecond)
+	c.ObserveCall("zone", "info", 2303, 2*time.Second)
+	c.ObserveCall("domain", "list", 1000, 10*time.Millisecond)
+	c.SetQueueDepth(3)
+	c.ObserveQueueWait(250 * time.Millisecond)
+
+	var b strings.Builder
+	if _, err := c.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP eurodns_calls_total Calls to the EuroDNS API by result code.
+# TYPE eurodns_calls_total counter
+eurodns_calls_total{namespace="domain",method="list",code="1000"} 1
+eurodns_calls_total{namespace="zone",method="info",code="1000"} 2
+eurodns_calls_total{namespace="zone",method="info",code="2303"} 1
+# HELP eurodns_call_duration_seconds Duration of calls to the EuroDNS API.
+# TYPE eurodns_call_duration_seconds histogram
+eurodns_call_duration_seconds_bucket{namespace="domain",method="list",le="0.1"} 1
+eurodns_call_duration_seconds_bucket{namespace="domain",method="list",le="1"} 1
+eurodns_call_duration_seconds_bucket{namespace="domain",method="list",le="+Inf"} 1
+eurodns_call_duration_seconds_sum{namespace="domain",method="list"} 0.01
+eurodns_call_duration_seconds_count{namespace="domain",method="list"} 1
+eurodns_call_duration_seconds_bucket{namespace="zone",method="info",le="0.1"} 1
+eurodns_call_duration_seconds_bucket{namespace="zone",method="info",le="1"} 2
+eurodns_call_duration_seconds_bucket{namespace="zone",method="info",le="+Inf"} 3
+eurodns_call_duration_seconds_sum{namespace="zone",method="info"} 2.55
+eurodns_call_duration_seconds_count{namespace="zone",method="info"} 3
+# HELP eurodns_queue_depth Scheduled calls waiting to be sent.
+# TYPE eurodns_queue_depth gauge
+eurodns_queue_depth 3
+# HELP eurodns_queue_wait_seconds Time scheduled calls waited to be sent.
+# TYPE eurodns_queue_wait_seconds histogram
+eurodns_queue_wait_seconds_bucket{le="0.1"} 0
+eurodns_queue_wait_seconds_bucket{le="1"} 1
+eurodns_queue_wait_seconds_bucket{le="+Inf"} 1
+eurodns_queue_wait_seconds_sum 0.25
+eurodns_queue_wait_seconds_count 1
+`
+	if b.String() != expected {
+		t.Errorf("unexpected output:\n%s", b.String())
+	}
+}
+
+func TestClient(t *testing.T) {
+	srv := eurodnstest.NewServer()
+	defer srv.Close()
+	srv.AddZone(api.Zone{Name: "example.org"})
+
+	m := New()
+	cc := srv.Config()
+	cc.Metrics = m
+	c, err := eurodnsgo.NewClient(cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := api.GetZoneList(context.TODO(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, line := range []string{
+		`eurodns_calls_total{namespace="zone",method="list",code="1000"} 1`,
+		`eurodns_call_duration_seconds_count{namespace="zone",method="list"} 1`,
+		`eurodns_queue_depth 0`,
+		`eurodns_queue_wait_seconds_count 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected %s in output:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package eurodnsgo
+
+import (
+	"context"
+	"net/http"
+)
+
+// Exchange holds a single call to the API while it passes through the
+// middleware of a client
+type Exchange struct {
+	Request *SoapRequest
+	// Envelope is the XML document of the request, it is updated when the
+	// request is sent so changes to Request by middleware are included
+	Envelope string
+	// Header holds additional headers for the HTTP request
+	Header http.Header
+
+	// HTTPRequest and HTTPResponse are set once the request was sent, the
+	// body of the response is already read into Body
+	HTTPRequest  *http.Request
+	HTTPResponse *http.Response
+	Body         []byte
+
+	// Code and Message hold the result of the response document
+	Code    int
+	Message string
+	// Result holds the contents of the resData element, which is decoded
+	// into the Result of the request after all middleware returned
+	Result []byte
+}
+
+// Handler performs an Exchange, the returned error is passed to the caller
+// of Client.Call or stored in the request by Client.Schedule
+type Handler func(ctx context.Context, e *Exchange) error
+
+// Middleware wraps the handler performing the calls of a client. It can
+// inspect or change the Exchange before and after calling next, or
+// short-circuit the call by setting the Result itself and not calling next
+// at all.
+type Middleware func(next Handler) Handler
+
+// chain wraps the handler in the middleware, the first middleware is the
+// outermost and sees every call first
+func chain(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
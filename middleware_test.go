@@ -0,0 +1,118 @@
+package eurodnsgo
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type zoneName struct {
+	XMLName xml.Name `xml:"resData"`
+	Name    string   `xml:"zone name"`
+}
+
+func testServer(t *testing.T, code int, calls *int) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if r.Header.Get("X-Test") != "middleware" {
+			t.Errorf("expected the header set by middleware, got %q", r.Header.Get("X-Test"))
+		}
+		w.Write([]byte(`<response><result code="` + strconv.Itoa(code) + `"><msg>message</msg></result><resData><zone:name>example.org</zone:name></resData></response>`))
+	}))
+}
+
+func testMiddlewareClient(srv *httptest.Server, middleware ...Middleware) Client {
+	c, _ := NewClient(ClientConfig{
+		Host:       strings.TrimPrefix(srv.URL, "https://"),
+		Username:   "username",
+		Password:   "password",
+		HTTPClient: srv.Client(),
+		Middleware: middleware,
+	})
+	return c
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	srv := testServer(t, 1000, &calls)
+	defer srv.Close()
+
+	var order []string
+	var code int
+	var envelope string
+	c := testMiddlewareClient(srv,
+		func(next Handler) Handler {
+			return func(ctx context.Context, e *Exchange) error {
+				order = append(order, "outer")
+				e.Header.Set("X-Test", "middleware")
+				err := next(ctx, e)
+				code = e.Code
+				return err
+			}
+		},
+		func(next Handler) Handler {
+			return func(ctx context.Context, e *Exchange) error {
+				order = append(order, "inner")
+				envelope = e.Envelope
+				return next(ctx, e)
+			}
+		},
+	)
+
+	var v zoneName
+	if err := c.Call(context.TODO(), NewSoapRequest("zone", "info", &v)); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "example.org" {
+		t.Errorf("expected the result to be decoded, got %q", v.Name)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" {
+		t.Errorf("unexpected middleware order %v", order)
+	}
+	if code != 1000 || !strings.Contains(envelope, "<zone:info>") || calls != 1 {
+		t.Errorf("unexpected exchange: code %d, envelope %q, %d calls", code, envelope, calls)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	calls := 0
+	srv := testServer(t, 1000, &calls)
+	defer srv.Close()
+
+	c := testMiddlewareClient(srv, func(next Handler) Handler {
+		return func(ctx context.Context, e *Exchange) error {
+			e.Result = []byte("<zone:name>example.net</zone:name>")
+			return nil
+		}
+	})
+
+	var v zoneName
+	if err := c.Call(context.TODO(), NewSoapRequest("zone", "info", &v)); err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "example.net" || calls != 0 {
+		t.Errorf("expected the result of the middleware without calls, got %q and %d calls", v.Name, calls)
+	}
+}
+
+func TestErrorResult(t *testing.T) {
+	calls := 0
+	srv := testServer(t, 2303, &calls)
+	defer srv.Close()
+
+	c := testMiddlewareClient(srv, func(next Handler) Handler {
+		return func(ctx context.Context, e *Exchange) error {
+			e.Header.Set("X-Test", "middleware")
+			return next(ctx, e)
+		}
+	})
+
+	err := c.Call(context.TODO(), NewSoapRequest("zone", "info", nil))
+	if e, ok := err.(*Error); !ok || e.Code != 2303 || e.Message != "message" {
+		t.Errorf("expected an API error, got %v", err)
+	}
+}
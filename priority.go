@@ -0,0 +1,118 @@
+package eurodnsgo
+
+import (
+	"context"
+	"errors"
+)
+
+// Priority determines the lane in which a scheduled call waits
+type Priority int
+
+const (
+	// PriorityLow is meant for background work like bulk exports
+	PriorityLow Priority = iota
+	// PriorityNormal is the priority of calls without a priority
+	PriorityNormal
+	// PriorityHigh is meant for urgent calls like ACME challenge updates
+	PriorityHigh
+)
+
+// ErrQueueFull is returned by Schedule when the lane of the call holds
+// QueueSize calls already
+var ErrQueueFull = errors.New("eurodnsgo: schedule queue is full")
+
+// defaultQueueSize is the capacity of every lane
+const defaultQueueSize = 32
+
+// laneWeights is the number of calls taken from a lane, indexed by
+// Priority, before the lower lanes get a turn. Every lane is served at
+// least once per round, so lower priorities are never starved.
+var laneWeights = [...]int{1, 2, 4}
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of the context which schedules calls with
+// the given priority
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set by WithPriority, or
+// PriorityNormal when none was set
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		if p < PriorityLow {
+			return PriorityLow
+		}
+		if p > PriorityHigh {
+			return PriorityHigh
+		}
+		return p
+	}
+	return PriorityNormal
+}
+
+// lanes holds a queue of scheduled calls per priority
+type lanes struct {
+	queues [len(laneWeights)]chan scheduledCall
+	// round holds the order in which the lanes are served
+	round []Priority
+	next  int
+}
+
+func newLanes(size int) *lanes {
+	l := &lanes{}
+	for p := range l.queues {
+		l.queues[p] = make(chan scheduledCall, size)
+	}
+	// interleave the lanes, highest first, so a round of 4:2:1 becomes
+	// high, normal, low, high, normal, high, high
+	for i := 0; i < laneWeights[PriorityHigh]; i++ {
+		for p := PriorityHigh; p >= PriorityLow; p-- {
+			if i < laneWeights[p] {
+				l.round = append(l.round, p)
+			}
+		}
+	}
+	return l
+}
+
+// push adds the call to the lane of its priority without blocking
+func (l *lanes) push(p Priority, sc scheduledCall) error {
+	select {
+	case l.queues[p] <- sc:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// pop returns the next call to perform. The lane whose turn it is comes
+// first, when it is empty the other lanes are tried from high to low.
+func (l *lanes) pop() (scheduledCall, bool) {
+	turn := l.round[l.next]
+	l.next = (l.next + 1) % len(l.round)
+
+	select {
+	case sc := <-l.queues[turn]:
+		return sc, true
+	default:
+	}
+	for p := PriorityHigh; p >= PriorityLow; p-- {
+		select {
+		case sc := <-l.queues[p]:
+			return sc, true
+		default:
+		}
+	}
+	return scheduledCall{}, false
+}
+
+// len returns the number of waiting calls in all lanes
+func (l *lanes) len() int {
+	n := 0
+	for _, q := range l.queues {
+		n += len(q)
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package eurodnsgo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLanesOrder(t *testing.T) {
+	l := newLanes(8)
+	for _, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		for i := 0; i < 8; i++ {
+			if err := l.push(p, scheduledCall{sr: NewSoapRequest("zone", "info", int(p))}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var order []Priority
+	for {
+		sc, ok := l.pop()
+		if !ok {
+			break
+		}
+		order = append(order, Priority(sc.sr.Result.(int)))
+	}
+
+	if len(order) != 24 {
+		t.Fatalf("expected 24 calls, got %d", len(order))
+	}
+	// the first round serves every lane by its weight
+	expected := []Priority{PriorityHigh, PriorityNormal, PriorityLow, PriorityHigh, PriorityNormal, PriorityHigh, PriorityHigh}
+	for i, p := range expected {
+		if order[i] != p {
+			t.Fatalf("expected order %v, got %v", expected, order[:len(expected)])
+		}
+	}
+	// once the high lane is empty its turns go to the other lanes
+	if order[23] != PriorityLow {
+		t.Errorf("expected a low priority call last, got %v", order)
+	}
+}
+
+func TestLanesFull(t *testing.T) {
+	l := newLanes(1)
+	if err := l.push(PriorityNormal, scheduledCall{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.push(PriorityNormal, scheduledCall{}); err != ErrQueueFull {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	if err := l.push(PriorityHigh, scheduledCall{}); err != nil {
+		t.Errorf("expected other lanes to accept calls, got %v", err)
+	}
+	if n := l.len(); n != 2 {
+		t.Errorf("expected 2 waiting calls, got %d", n)
+	}
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.TODO()
+	if p := PriorityFromContext(ctx); p != PriorityNormal {
+		t.Errorf("expected normal priority by default, got %d", p)
+	}
+	if p := PriorityFromContext(WithPriority(ctx, PriorityHigh)); p != PriorityHigh {
+		t.Errorf("expected high priority, got %d", p)
+	}
+	if p := PriorityFromContext(WithPriority(ctx, 10)); p != PriorityHigh {
+		t.Errorf("expected out of range priorities to be clamped, got %d", p)
+	}
+}
@@ -5,11 +5,11 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/xml"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
@@ -126,6 +126,9 @@ type SoapRequest struct {
 	Method    string
 	Result    interface{}
 	IsTest    bool // default false
+
+	// err holds the error encountered while processing a scheduled request
+	err error
 }
 
 // Entity is here to provide Param interface
@@ -149,6 +152,31 @@ func (sr *SoapRequest) Attrs() []Attr {
 	return v
 }
 
+// Err returns the error encountered while the request was processed by
+// Client.Schedule, or nil when it completed successfully.
+func (sr *SoapRequest) Err() error {
+	return sr.err
+}
+
+// DecodeResult unmarshals the contents of a resData element, as returned
+// through the channel of Client.Schedule, into the Result of the request
+func (sr *SoapRequest) DecodeResult(contents []byte) error {
+	if sr.Result == nil {
+		return nil
+	}
+
+	// wrap the inner content
+	content := fmt.Sprintf("<resData>%s</resData>", string(contents))
+
+	return xml.Unmarshal([]byte(content), sr.Result)
+}
+
+// ReadOnly reports whether the request only retrieves data, which is the
+// case for the list and info methods
+func (sr *SoapRequest) ReadOnly() bool {
+	return sr.Method == "list" || sr.Method == "info"
+}
+
 // NewSoapRequest creates a new SoapRequest instance
 func NewSoapRequest(domain, method string, result interface{}) *SoapRequest {
 	return &SoapRequest{
@@ -173,10 +201,22 @@ func (sr *SoapRequest) getEnvelope() string {
 }
 
 type soapClient struct {
-	login     string
-	password  string
-	host      string
-	callDelay int
+	credentials CredentialsProvider
+	host        string
+	httpClient  *http.Client
+	handler     Handler
+}
+
+// Error is returned when the EuroDNS server responds with a result code
+// other than 1000, which signals a successful command
+type Error struct {
+	Code    int
+	Message string
+}
+
+// Error returns the message sent along with the result code
+func (e *Error) Error() string {
+	return e.Message
 }
 
 type soapResult struct {
@@ -198,31 +238,50 @@ type soapEnvelope struct {
 }
 
 func (s *soapClient) call(ctx context.Context, req *SoapRequest) ([]byte, error) {
+	e := &Exchange{
+		Request:  req,
+		Envelope: req.PrepareContent(),
+		Header:   make(http.Header),
+	}
+	if err := s.handler(ctx, e); err != nil {
+		return nil, err
+	}
+
+	// parse SOAP response into given result interface
+	if err := e.Request.DecodeResult(e.Result); err != nil {
+		return nil, err
+	}
+	return e.Result, nil
+}
+
+// send is the innermost Handler of the middleware chain, which performs the
+// HTTP request
+func (s *soapClient) send(ctx context.Context, e *Exchange) error {
+	e.Envelope = e.Request.PrepareContent()
+
 	// get http request for soap request
-	httpReq, err := s.httpReqForSoapRequest(ctx, *req)
+	httpReq, err := s.httpReqForSoapRequest(ctx, *e.Request)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	for k, v := range e.Header {
+		httpReq.Header[k] = v
 	}
+	e.HTTPRequest = httpReq
 
-	http := &http.Client{}
-	res, err := http.Do(httpReq)
+	res, err := s.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("request error:\n%s", err.Error())
+		return fmt.Errorf("request error:\n%s", err.Error())
 	}
 	defer res.Body.Close()
+	e.HTTPResponse = res
 
 	// read entire response body
-	b, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	if e.Body, err = ioutil.ReadAll(res.Body); err != nil {
+		return err
 	}
 
-	// parse SOAP response into given result interface
-	parsed, err := parseSoapResponse(b, req)
-	if err != nil {
-		return nil, err
-	}
-	return parsed, nil
+	return parseSoapResponse(e)
 }
 
 // httpReqForSoapRequest creates the HTTP request for a specific SoapRequest
@@ -237,11 +296,15 @@ func (s soapClient) httpReqForSoapRequest(ctx context.Context, req SoapRequest)
 	}
 	httpReq = httpReq.WithContext(ctx)
 
-	authStr := base64.StdEncoding.EncodeToString([]byte(s.login + ":" + s.password))
+	creds, err := s.credentials.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	authStr := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password))
 	httpReq.Header.Add("Authorization", "Basic "+authStr)
 	httpReq.Header.Add("Connection", "close")
 	httpReq.Header.Add("Content-type", "application/x-www-form-urlencoded")
-	httpReq.Header.Add("Content-length", string(b.Len()))
+	httpReq.Header.Add("Content-length", strconv.Itoa(b.Len()))
 
 	return httpReq, nil
 }
@@ -300,22 +363,18 @@ func getSOAPArgs(pc ParamsContainer) []byte {
 	return buf.Bytes()
 }
 
-func parseSoapResponse(data []byte, sr *SoapRequest) ([]byte, error) {
+func parseSoapResponse(e *Exchange) error {
 	var env soapEnvelope
-	if err := xml.Unmarshal(data, &env); err != nil {
-		return nil, err
+	if err := xml.Unmarshal(e.Body, &env); err != nil {
+		return err
 	}
 
+	e.Code = env.Result.Code
+	e.Message = env.Result.Message
 	if env.Result.Code != 1000 {
-		return nil, errors.New(env.Result.Message)
-	}
-
-	// wrap the inner content
-	content := fmt.Sprintf("<resData>%s</resData>", string(env.Data.Contents))
-
-	if err := xml.Unmarshal([]byte(content), sr.Result); err != nil {
-		return nil, err
+		return &Error{env.Result.Code, env.Result.Message}
 	}
 
-	return env.Data.Contents, nil
+	e.Result = env.Data.Contents
+	return nil
 }
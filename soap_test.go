@@ -1,6 +1,8 @@
 package eurodnsgo
 
 import (
+	"context"
+	"strconv"
 	"testing"
 )
 
@@ -71,3 +73,20 @@ func TestAddParam(t *testing.T) {
 		t.Fatalf("expect PrepareContent output to match our expected result, received \"%s\"", e)
 	}
 }
+
+func TestContentLength(t *testing.T) {
+	c, err := NewClient(ClientConfig{Host: "host", Username: "username", Password: "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sr := NewSoapRequest("domain", "list", nil)
+	req, err := c.(*client).sc.httpReqForSoapRequest(context.TODO(), *sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if l := req.Header.Get("Content-length"); l != strconv.Itoa(len(sr.getEnvelope())) {
+		t.Errorf("expected the length of the envelope, got %q", l)
+	}
+}
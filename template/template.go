@@ -0,0 +1,144 @@
+// Package template provisions a common set of records on many zones. The
+// Host and Data of the records may contain text/template placeholders like
+// {{.Domain}} and {{.IPv4}}, which are rendered for every zone.
+package template
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/omines/eurodnsgo"
+	"github.com/omines/eurodnsgo/api"
+)
+
+// Vars holds the values available to the placeholders of a Template
+type Vars struct {
+	// Domain defaults to the name of the zone the template is applied to
+	Domain string
+	IPv4   string
+	IPv6   string
+	// Values holds any additional values, available as {{.Values.name}}
+	Values map[string]string
+}
+
+// Template defines a set of records to provision on a zone.
+//
+// By default the template owns every RRset it defines, so other records
+// with the same host and type are changed or removed when applying it.
+// In Additive mode existing records are never changed or removed, apart
+// from the TTL and priority of records matching a template record.
+type Template struct {
+	Records  []api.Record
+	Additive bool
+}
+
+func render(name, text string, v Vars) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	t, err := texttemplate.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, v); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Render returns the records of the template with all placeholders
+// replaced by the given values
+func (t Template) Render(v Vars) ([]api.Record, error) {
+	res := make([]api.Record, len(t.Records))
+	for i, r := range t.Records {
+		var err error
+		if r.Host, err = render("host", r.Host, v); err != nil {
+			return nil, fmt.Errorf("template record %d: %s", i, err)
+		}
+		if r.Data, err = render("data", r.Data, v); err != nil {
+			return nil, fmt.Errorf("template record %d: %s", i, err)
+		}
+		r.ID = 0
+		res[i] = r
+	}
+	return res, nil
+}
+
+// desired returns the desired state of the live zone after applying the
+// rendered records
+func (t Template) desired(live api.Zone, rendered []api.Record) api.Zone {
+	z := api.Zone{Name: live.Name}
+	for i := range rendered {
+		z.Records = append(z.Records, &rendered[i])
+	}
+
+	for _, l := range live.Records {
+		keep := true
+		for _, r := range rendered {
+			sameRRSet := r.Type == l.Type && live.NormalizeHost(r.Host) == live.NormalizeHost(l.Host)
+			if sameRRSet && (!t.Additive || strings.TrimSpace(r.Data) == strings.TrimSpace(l.Data)) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			z.Records = append(z.Records, l)
+		}
+	}
+	return z
+}
+
+func (t Template) plan(live api.Zone, v Vars, validate bool) (api.Plan, error) {
+	if v.Domain == "" {
+		v.Domain = live.Name
+	}
+
+	rendered, err := t.Render(v)
+	if err != nil {
+		return api.Plan{}, err
+	}
+
+	if validate {
+		z := api.Zone{Name: live.Name}
+		for i := range rendered {
+			z.Records = append(z.Records, &rendered[i])
+		}
+		if err := z.Validate(); err != nil {
+			return api.Plan{}, err
+		}
+	}
+
+	return api.Diff(live, t.desired(live, rendered)), nil
+}
+
+// Plan renders the template for a zone and returns the Plan to apply it
+// to the live zone. The rendered records are validated first, unless the
+// context was created by api.SkipValidation.
+func (t Template) Plan(ctx context.Context, c eurodnsgo.Client, zone string, v Vars) (api.Plan, error) {
+	live, err := api.GetZoneInfo(ctx, c, zone)
+	if err != nil {
+		return api.Plan{}, err
+	}
+
+	return t.plan(live, v, !api.ValidationSkipped(ctx))
+}
+
+// Apply renders the template for a zone and applies it to the live zone,
+// returning the executed Plan
+func (t Template) Apply(ctx context.Context, c eurodnsgo.Client, zone string, v Vars) (api.Plan, error) {
+	ctx, span := eurodnsgo.StartSpan(ctx, c, "eurodns template.Apply", eurodnsgo.Attr{Key: eurodnsgo.AttrZone, Value: zone})
+
+	p, err := t.Plan(ctx, c, zone, v)
+	if err == nil {
+		err = api.ApplyPlan(ctx, c, p)
+	}
+
+	eurodnsgo.EndSpan(span, err)
+	return p, err
+}
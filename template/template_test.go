@@ -0,0 +1,82 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/omines/eurodnsgo/api"
+)
+
+var mailTemplate = Template{
+	Records: []api.Record{
+		{Host: "@", Type: api.RecordTypeMX, TTL: 3600, Priority: 10, Data: "mx.{{.Domain}}."},
+		{Host: "@", Type: api.RecordTypeTXT, TTL: 3600, Data: "v=spf1 ip4:{{.IPv4}} -all"},
+		{Host: "www", Type: api.RecordTypeA, TTL: 3600, Data: "{{.IPv4}}"},
+		{Host: "{{.Values.selector}}._domainkey", Type: api.RecordTypeTXT, TTL: 3600, Data: "v=DKIM1; p=KEY"},
+	},
+}
+
+var templateVars = Vars{IPv4: "192.0.2.1", Values: map[string]string{"selector": "s1"}}
+
+func TestRender(t *testing.T) {
+	v := templateVars
+	v.Domain = "example.org"
+
+	records, err := mailTemplate.Render(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0].Data != "mx.example.org." || records[1].Data != "v=spf1 ip4:192.0.2.1 -all" || records[3].Host != "s1._domainkey" {
+		t.Errorf("unexpected rendered records %+v", records)
+	}
+
+	if _, err := mailTemplate.Render(Vars{Domain: "example.org"}); err == nil {
+		t.Error("expected missing values to be reported")
+	}
+}
+
+func liveZone() api.Zone {
+	return api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			{ID: 1, Host: "@", Type: api.RecordTypeMX, TTL: 300, Priority: 10, Data: "mx.example.org."},
+			{ID: 2, Host: "@", Type: api.RecordTypeMX, TTL: 3600, Priority: 20, Data: "backup.example.net."},
+			{ID: 3, Host: "www", Type: api.RecordTypeA, TTL: 3600, Data: "198.51.100.1"},
+			{ID: 4, Host: "ftp", Type: api.RecordTypeA, TTL: 3600, Data: "198.51.100.2"},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	p, err := mailTemplate.plan(liveZone(), templateVars, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the MX TTL and the www address change, the backup MX is removed and
+	// both TXT records are added
+	if len(p.Records(api.Change)) != 2 || len(p.Records(api.Remove)) != 1 || len(p.Records(api.Add)) != 2 {
+		t.Errorf("unexpected plan %s", p)
+	}
+	if r := p.Records(api.Remove); len(r) == 1 && r[0].ID != 2 {
+		t.Errorf("expected the backup MX to be removed, got %+v", r[0])
+	}
+}
+
+func TestPlanAdditive(t *testing.T) {
+	tpl := mailTemplate
+	tpl.Additive = true
+
+	p, err := tpl.plan(liveZone(), templateVars, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the TTL of the matching MX changes, the www address is added
+	// next to the existing one
+	if len(p.Records(api.Remove)) != 0 || len(p.Records(api.Change)) != 1 || len(p.Records(api.Add)) != 3 {
+		t.Errorf("unexpected plan %s", p)
+	}
+	if r := p.Records(api.Change); len(r) == 1 && r[0].ID != 1 {
+		t.Errorf("expected only record 1 to change, got %+v", r[0])
+	}
+}
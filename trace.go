@@ -0,0 +1,114 @@
+package eurodnsgo
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer starts spans, its shape follows the OpenTelemetry tracing API so
+// an adapter around an OpenTelemetry tracer only needs to convert the
+// attributes:
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, eurodnsgo.Span) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	// Start starts a span as child of the span in the context, and returns
+	// a context holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation
+type Span interface {
+	SetAttributes(attrs ...Attr)
+	RecordError(err error)
+	End()
+}
+
+// Attribute keys set on the spans of the client
+const (
+	AttrNamespace  = "eurodns.namespace"
+	AttrMethod     = "eurodns.method"
+	AttrResultCode = "eurodns.result_code"
+	AttrScheduled  = "eurodns.scheduled"
+	// AttrQueueWait and AttrNetworkTime hold durations in milliseconds
+	AttrQueueWait   = "eurodns.queue_wait_ms"
+	AttrNetworkTime = "eurodns.network_ms"
+	AttrZone        = "eurodns.zone"
+)
+
+type spanKey struct{}
+
+// TracerOf returns the Tracer of a client created by NewClient, or of any
+// client with a Tracer method like clients wrapping another client. It
+// returns a Tracer doing nothing for other clients.
+func TracerOf(c Client) Tracer {
+	if tc, ok := c.(interface{ Tracer() Tracer }); ok {
+		if t := tc.Tracer(); t != nil {
+			return t
+		}
+	}
+	return nopTracer{}
+}
+
+// StartSpan starts a span using the Tracer of the client, see TracerOf. The
+// api package uses it to trace operations consisting of multiple calls, so
+// the spans of the calls become children of the span of the operation.
+func StartSpan(ctx context.Context, c Client, name string, attrs ...Attr) (context.Context, Span) {
+	return startSpan(TracerOf(c), ctx, name, attrs...)
+}
+
+func startSpan(t Tracer, ctx context.Context, name string, attrs ...Attr) (context.Context, Span) {
+	ctx, span := t.Start(ctx, name)
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the span started by StartSpan or a client, or a
+// span doing nothing when the context holds no span
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span
+	}
+	return nopSpan{}
+}
+
+// EndSpan records the error, when it is not nil, and ends the span
+func EndSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attrs ...Attr) {}
+func (nopSpan) RecordError(err error)       {}
+func (nopSpan) End()                        {}
+
+// tracing returns the middleware adding the result code and network time
+// to the span of the call
+func tracing() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, e *Exchange) error {
+			start := time.Now()
+			err := next(ctx, e)
+
+			SpanFromContext(ctx).SetAttributes(
+				Attr{AttrResultCode, e.Code},
+				Attr{AttrNetworkTime, time.Since(start).Nanoseconds() / int64(time.Millisecond)},
+			)
+			return err
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package eurodnsgo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attr) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.err = err }
+func (s *testSpan) End()                  { s.ended = true }
+
+type testParentKey struct{}
+
+// testTracer keeps all started spans
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(testParentKey{}).(*testSpan)
+	s := &testSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, testParentKey{}, s), s
+}
+
+func TestTracing(t *testing.T) {
+	srv := resultServer(2303, nil)
+	defer srv.Close()
+
+	tracer := &testTracer{}
+	c, _ := NewClient(ClientConfig{
+		Host:       strings.TrimPrefix(srv.URL, "https://"),
+		Username:   "username",
+		Password:   "password",
+		HTTPClient: srv.Client(),
+		Logger:     NopLogger,
+		Tracer:     tracer,
+	})
+
+	ctx, parent := StartSpan(context.TODO(), c, "operation")
+	sr := NewSoapRequest("zone", "info", nil)
+	ch, _ := c.Schedule(ctx, sr)
+	<-ch
+	parent.End()
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+	s := tracer.spans[1]
+	if s.name != "eurodns zone:info" || s.parent != tracer.spans[0] || !s.ended {
+		t.Errorf("expected an ended child span of the operation, got %+v", s)
+	}
+	if s.attrs[AttrNamespace] != "zone" || s.attrs[AttrMethod] != "info" || s.attrs[AttrResultCode] != 2303 || s.attrs[AttrScheduled] != true {
+		t.Errorf("unexpected attributes %v", s.attrs)
+	}
+	if _, ok := s.attrs[AttrQueueWait]; !ok {
+		t.Error("expected the queue wait of a scheduled call")
+	}
+	if _, ok := s.attrs[AttrNetworkTime]; !ok {
+		t.Error("expected the network time of the call")
+	}
+	if s.err == nil || s.err != sr.Err() {
+		t.Errorf("expected the error of the call to be recorded, got %v", s.err)
+	}
+}
+
+func TestClientTracer(t *testing.T) {
+	srv := resultServer(1000, nil)
+	defer srv.Close()
+
+	tracer := &testTracer{}
+	c, _ := NewClient(ClientConfig{
+		Host:       strings.TrimPrefix(srv.URL, "https://"),
+		Username:   "username",
+		Password:   "password",
+		HTTPClient: srv.Client(),
+		Logger:     NopLogger,
+		Tracer:     tracer,
+	})
+
+	if err := c.Call(context.TODO(), NewSoapRequest("zone", "info", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracer.spans) != 1 || tracer.spans[0].attrs[AttrScheduled] != false || !tracer.spans[0].ended {
+		t.Errorf("expected a single ended span for the call, got %+v", tracer.spans)
+	}
+}
+
+func TestTracerOf(t *testing.T) {
+	c, _ := NewClient(ClientConfig{Host: "localhost", Username: "username", Password: "password"})
+	if _, ok := TracerOf(c).(nopTracer); !ok {
+		t.Error("expected no tracing without a configured Tracer")
+	}
+	if _, ok := TracerOf(nil).(nopTracer); !ok {
+		t.Error("expected no tracing for clients without a Tracer method")
+	}
+}
@@ -0,0 +1,365 @@
+package zonefile
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/omines/eurodnsgo/api"
+)
+
+type token struct {
+	// raw holds the token as written, including quotes
+	raw string
+	// text holds the unquoted and unescaped contents
+	text   string
+	quoted bool
+}
+
+type line struct {
+	num        int
+	blankOwner bool
+	tokens     []token
+}
+
+// lex splits master file contents into logical lines. Comments are dropped
+// and lines spanning multiple physical lines by parentheses are joined.
+func lex(input string) ([]line, error) {
+	var (
+		lines []line
+		cur   = line{num: 1}
+		depth int
+		num   = 1
+	)
+
+	flush := func() {
+		if len(cur.tokens) > 0 {
+			lines = append(lines, cur)
+		}
+		cur = line{num: num}
+	}
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\n':
+			num++
+			if depth == 0 {
+				flush()
+			}
+		case c == ';':
+			for i+1 < len(input) && input[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parenthesis", num)
+			}
+			depth--
+		case c == ' ' || c == '\t' || c == '\r':
+			if i == 0 || input[i-1] == '\n' {
+				if depth == 0 {
+					cur.blankOwner = true
+				}
+			}
+		case c == '"':
+			start := i
+			var text []byte
+			for i++; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\n' {
+					num++
+				}
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+					if n, ok := decimalEscape(input[i:]); ok {
+						text = append(text, n)
+						i += 2
+						continue
+					}
+				}
+				text = append(text, input[i])
+			}
+			if i == len(input) {
+				return nil, fmt.Errorf("line %d: unterminated quoted string", num)
+			}
+			cur.tokens = append(cur.tokens, token{raw: input[start : i+1], text: string(text), quoted: true})
+		default:
+			start := i
+			for i+1 < len(input) && !strings.ContainsRune(" \t\r\n;()\"", rune(input[i+1])) {
+				i++
+			}
+			raw := input[start : i+1]
+			cur.tokens = append(cur.tokens, token{raw: raw, text: raw})
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parenthesis", num)
+	}
+	flush()
+
+	return lines, nil
+}
+
+// decimalEscape decodes the \DDD escape sequence at the start of s
+func decimalEscape(s string) (byte, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:3])
+	if err != nil || n > 255 {
+		return 0, false
+	}
+	return byte(n), true
+}
+
+// parseTTL parses a TTL in seconds or in BIND notation like 1h30m
+func parseTTL(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, n >= 0
+	}
+
+	var total, n int
+	var digits bool
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			n = n*10 + int(c-'0')
+			digits = true
+			continue
+		}
+		if !digits {
+			return 0, false
+		}
+		switch c {
+		case 's':
+		case 'm':
+			n *= 60
+		case 'h':
+			n *= 3600
+		case 'd':
+			n *= 86400
+		case 'w':
+			n *= 604800
+		default:
+			return 0, false
+		}
+		total += n
+		n, digits = 0, false
+	}
+	if digits {
+		return 0, false
+	}
+	return total, true
+}
+
+func isClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "CS", "HS":
+		return true
+	}
+	return false
+}
+
+// qualify returns the absolute form of a domain name relative to origin
+func qualify(name, origin string) string {
+	if name == "@" {
+		return origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "." + origin
+}
+
+// relativize returns the host of an absolute name relative to the apex,
+// or the absolute name when it lies outside of the zone
+func relativize(name, apex string) string {
+	if strings.EqualFold(name, apex) {
+		return "@"
+	}
+	if len(name) > len(apex) && strings.EqualFold(name[len(name)-len(apex)-1:], "."+apex) {
+		return name[:len(name)-len(apex)-1]
+	}
+	return name
+}
+
+// Parse reads a master file and returns its records. Owner names are
+// returned relative to origin, which is also the initial $ORIGIN. Domain
+// names inside record data are made absolute.
+func Parse(r io.Reader, origin string) ([]api.Record, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := lex(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("zonefile: %s", err)
+	}
+
+	apex := fqdn(origin)
+	p := parser{origin: apex, apex: apex, defaultTTL: -1, lastTTL: -1}
+
+	var records []api.Record
+	for _, l := range lines {
+		rec, ok, err := p.parseLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("zonefile: line %d: %s", l.num, err)
+		}
+		if ok {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+type parser struct {
+	origin     string
+	apex       string
+	defaultTTL int
+	lastTTL    int
+	lastOwner  string
+}
+
+func (p *parser) parseLine(l line) (api.Record, bool, error) {
+	var rec api.Record
+	toks := l.tokens
+
+	switch strings.ToUpper(toks[0].raw) {
+	case "$ORIGIN":
+		if len(toks) < 2 {
+			return rec, false, fmt.Errorf("$ORIGIN requires a domain name")
+		}
+		p.origin = qualify(toks[1].text, p.origin)
+		return rec, false, nil
+	case "$TTL":
+		if len(toks) < 2 {
+			return rec, false, fmt.Errorf("$TTL requires a value")
+		}
+		ttl, ok := parseTTL(toks[1].text)
+		if !ok {
+			return rec, false, fmt.Errorf("invalid TTL %q", toks[1].text)
+		}
+		p.defaultTTL = ttl
+		return rec, false, nil
+	case "$INCLUDE", "$GENERATE":
+		return rec, false, fmt.Errorf("%s is not supported", toks[0].raw)
+	}
+
+	if !l.blankOwner {
+		p.lastOwner = qualify(toks[0].text, p.origin)
+		toks = toks[1:]
+	} else if p.lastOwner == "" {
+		return rec, false, fmt.Errorf("record without owner name")
+	}
+	rec.Host = relativize(p.lastOwner, p.apex)
+
+	ttl := -1
+	for len(toks) > 0 {
+		if n, ok := parseTTL(toks[0].text); ok && ttl < 0 {
+			ttl = n
+		} else if !isClass(toks[0].text) {
+			break
+		}
+		toks = toks[1:]
+	}
+	if len(toks) == 0 {
+		return rec, false, fmt.Errorf("missing record type")
+	}
+	rec.Type = api.RecordType(strings.ToUpper(toks[0].text))
+	rdata := toks[1:]
+
+	switch {
+	case ttl >= 0:
+		p.lastTTL = ttl
+	case p.defaultTTL >= 0:
+		ttl = p.defaultTTL
+	case p.lastTTL >= 0:
+		ttl = p.lastTTL
+	default:
+		ttl = 0
+	}
+	rec.TTL = ttl
+
+	if err := p.parseData(&rec, rdata); err != nil {
+		return rec, false, err
+	}
+	return rec, true, nil
+}
+
+func (p *parser) parseData(rec *api.Record, rdata []token) error {
+	need := func(n int) error {
+		if len(rdata) < n {
+			return fmt.Errorf("%s record requires %d data fields, got %d", rec.Type, n, len(rdata))
+		}
+		return nil
+	}
+	priority := func() error {
+		n, err := strconv.Atoi(rdata[0].text)
+		if err != nil {
+			return fmt.Errorf("invalid %s priority %q", rec.Type, rdata[0].text)
+		}
+		rec.Priority = n
+		return nil
+	}
+
+	switch rec.Type {
+	case api.RecordTypeCNAME, api.RecordTypeNS, api.RecordTypePTR, api.RecordTypeALIAS:
+		if err := need(1); err != nil {
+			return err
+		}
+		rec.Data = qualify(rdata[0].text, p.origin)
+	case api.RecordTypeMX:
+		if err := need(2); err != nil {
+			return err
+		}
+		if err := priority(); err != nil {
+			return err
+		}
+		rec.Data = qualify(rdata[1].text, p.origin)
+	case api.RecordTypeSRV:
+		if err := need(4); err != nil {
+			return err
+		}
+		if err := priority(); err != nil {
+			return err
+		}
+		rec.Data = fmt.Sprintf("%s %s %s", rdata[1].text, rdata[2].text, qualify(rdata[3].text, p.origin))
+	case api.RecordTypeTXT, api.RecordTypeSPF:
+		var parts []string
+		for _, t := range rdata {
+			parts = append(parts, t.text)
+		}
+		rec.Data = api.TXTData{Strings: parts}.String()
+	case api.RecordTypeSOA:
+		if err := need(7); err != nil {
+			return err
+		}
+		rec.Data = qualify(rdata[0].text, p.origin)
+		rec.RespPerson = qualify(rdata[1].text, p.origin)
+		var minimum int
+		for i, v := range []*int{&rec.Refresh, &rec.Retry, &rec.Expire, &minimum} {
+			n, ok := parseTTL(rdata[i+3].text)
+			if !ok {
+				return fmt.Errorf("invalid SOA timer %q", rdata[i+3].text)
+			}
+			*v = n
+		}
+		// EuroDNS stores the minimum as the TTL of the SOA record, see
+		// api.SOAData. The lesser of both is the effective negative caching
+		// TTL according to RFC 2308.
+		if rec.TTL == 0 || minimum < rec.TTL {
+			rec.TTL = minimum
+		}
+	default:
+		var parts []string
+		for _, t := range rdata {
+			parts = append(parts, t.raw)
+		}
+		rec.Data = strings.Join(parts, " ")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Package zonefile converts EuroDNS zones from and to RFC 1035 master
+// files, as used by BIND and most other DNS servers.
+package zonefile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omines/eurodnsgo/api"
+)
+
+// maxStringLength is the maximum length of a single character-string
+// inside TXT data
+const maxStringLength = 255
+
+// Marshal returns the master file representation of a zone
+func Marshal(z api.Zone) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, z); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write renders a zone as an RFC 1035 master file. The SOA record, when
+// present, is written first. Record hosts are written relative to the zone
+// origin, record data is written as is.
+//
+// The API does not expose the serial of a zone, so the SOA record gets a
+// serial in the conventional YYYYMMDDnn format for the current time, with
+// the hour of the day as revision. Use WriteSerial to choose the serial.
+func Write(w io.Writer, z api.Zone) error {
+	return WriteSerial(w, z, dateSerial(time.Now()))
+}
+
+// WriteSerial renders a zone like Write, using the given serial for the
+// SOA record. Nil records are skipped.
+func WriteSerial(w io.Writer, z api.Zone, serial uint32) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "$ORIGIN %s\n", fqdn(z.Name))
+	for _, r := range z.Filter(func(r *api.Record) bool { return r.Type == api.RecordTypeSOA }) {
+		writeSOA(bw, r, serial)
+	}
+	for _, r := range z.Filter(func(r *api.Record) bool { return r.Type != api.RecordTypeSOA }) {
+		writeRecord(bw, r)
+	}
+
+	return bw.Flush()
+}
+
+// dateSerial returns the YYYYMMDDHH serial for a time
+func dateSerial(t time.Time) uint32 {
+	t = t.UTC()
+	return uint32(t.Year())*1000000 + uint32(t.Month())*10000 + uint32(t.Day())*100 + uint32(t.Hour())
+}
+
+// writeSOA writes the SOA record, its TTL doubles as the minimum as
+// described by api.SOAData
+func writeSOA(w io.Writer, r *api.Record, serial uint32) {
+	fmt.Fprintf(w, "%s\t%d\tIN\tSOA\t%s %s (\n", owner(r.Host), r.TTL, r.Data, r.RespPerson)
+	fmt.Fprintf(w, "\t\t\t\t%d\t; serial\n", serial)
+	fmt.Fprintf(w, "\t\t\t\t%d\t; refresh\n", r.Refresh)
+	fmt.Fprintf(w, "\t\t\t\t%d\t; retry\n", r.Retry)
+	fmt.Fprintf(w, "\t\t\t\t%d\t; expire\n", r.Expire)
+	fmt.Fprintf(w, "\t\t\t\t%d )\t; minimum\n", r.TTL)
+}
+
+func writeRecord(w io.Writer, r *api.Record) {
+	ttl := ""
+	if r.TTL > 0 {
+		ttl = strconv.Itoa(r.TTL)
+	}
+
+	var data string
+	switch r.Type {
+	case api.RecordTypeMX, api.RecordTypeSRV:
+		data = fmt.Sprintf("%d %s", r.Priority, r.Data)
+	case api.RecordTypeTXT, api.RecordTypeSPF:
+		data = quoteStrings(splitTXT(r.Data))
+	default:
+		data = r.Data
+	}
+
+	fmt.Fprintf(w, "%s\t%s\tIN\t%s\t%s\n", owner(r.Host), ttl, r.Type, data)
+}
+
+func owner(host string) string {
+	if host == "" {
+		return "@"
+	}
+	return host
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// splitTXT returns the character-strings contained in TXT data
+func splitTXT(data string) []string {
+	d, err := api.ParseTXT(data)
+	if err != nil {
+		return []string{data}
+	}
+	return d.Strings
+}
+
+// quoteStrings quotes and escapes the strings, splitting up any strings
+// exceeding the maximum length of a character-string
+func quoteStrings(parts []string) string {
+	var quoted []string
+	for _, p := range parts {
+		for {
+			chunk := p
+			if len(chunk) > maxStringLength {
+				chunk = chunk[:maxStringLength]
+			}
+			chunk = strings.Replace(chunk, `\`, `\\`, -1)
+			chunk = strings.Replace(chunk, `"`, `\"`, -1)
+			quoted = append(quoted, `"`+chunk+`"`)
+			if len(p) <= maxStringLength {
+				break
+			}
+			p = p[maxStringLength:]
+		}
+	}
+	return strings.Join(quoted, " ")
+}
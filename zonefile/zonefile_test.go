@@ -0,0 +1,156 @@
+package zonefile
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omines/eurodnsgo/api"
+)
+
+var exampleZone = `$ORIGIN example.org.
+$TTL 1h
+@	IN	SOA	ns1 hostmaster (
+		2019010101 ; serial
+		10800      ; refresh
+		3600       ; retry
+		1209600    ; expire
+		300 )      ; minimum
+	IN	NS	ns1.example.net.
+	IN	MX	10 mail
+	IN	MX	0 backup.example.net.
+www	300	IN	A	192.0.2.1
+	IN 300	AAAA	2001:db8::1
+ftp		CNAME	www
+txt		TXT	"v=spf1 -all"
+		SPF	"v=spf1 -all"
+dkim		TXT	( "first part "
+		  "second \"part\"" )
+_sip._tcp	SRV	10 60 5060 sip.example.org.
+$ORIGIN sub.example.org.
+host		A	192.0.2.2
+other.org.	A	192.0.2.3
+`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(strings.NewReader(exampleZone), "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []api.Record{
+		{Host: "@", Type: api.RecordTypeSOA, TTL: 300, Data: "ns1.example.org.", RespPerson: "hostmaster.example.org.", Refresh: 10800, Retry: 3600, Expire: 1209600},
+		{Host: "@", Type: api.RecordTypeNS, TTL: 3600, Data: "ns1.example.net."},
+		{Host: "@", Type: api.RecordTypeMX, TTL: 3600, Priority: 10, Data: "mail.example.org."},
+		{Host: "@", Type: api.RecordTypeMX, TTL: 3600, Data: "backup.example.net."},
+		{Host: "www", Type: api.RecordTypeA, TTL: 300, Data: "192.0.2.1"},
+		{Host: "www", Type: api.RecordTypeAAAA, TTL: 300, Data: "2001:db8::1"},
+		{Host: "ftp", Type: api.RecordTypeCNAME, TTL: 3600, Data: "www.example.org."},
+		{Host: "txt", Type: api.RecordTypeTXT, TTL: 3600, Data: "v=spf1 -all"},
+		{Host: "txt", Type: api.RecordTypeSPF, TTL: 3600, Data: "v=spf1 -all"},
+		{Host: "dkim", Type: api.RecordTypeTXT, TTL: 3600, Data: `"first part " "second \"part\""`},
+		{Host: "_sip._tcp", Type: api.RecordTypeSRV, TTL: 3600, Priority: 10, Data: "60 5060 sip.example.org."},
+		{Host: "host.sub", Type: api.RecordTypeA, TTL: 3600, Data: "192.0.2.2"},
+		{Host: "other.org.", Type: api.RecordTypeA, TTL: 3600, Data: "192.0.2.3"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, parsed %d: %+v", len(expected), len(records), records)
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(records[i], expected[i]) {
+			t.Errorf("record %d:\nexpected %+v\nreceived %+v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"www IN A (192.0.2.1\n",
+		"www IN TXT \"unterminated\n",
+		"\tIN A 192.0.2.1\n",
+		"@ IN MX mail\n",
+		"$INCLUDE other.zone\n",
+	} {
+		if _, err := Parse(strings.NewReader(input), "example.org"); err == nil {
+			t.Errorf("expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	records, err := Parse(strings.NewReader(exampleZone), "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	z := api.Zone{Name: "example.org"}
+	for i := range records {
+		z.Records = append(z.Records, &records[i])
+	}
+	if err := z.Validate(); err != nil {
+		t.Errorf("expected the parsed zone to be valid, got %s", err)
+	}
+
+	b, err := Marshal(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(strings.NewReader(string(b)), "example.org")
+	if err != nil {
+		t.Fatalf("%s\n%s", err, b)
+	}
+	if !reflect.DeepEqual(records, parsed) {
+		t.Errorf("round trip mismatch:\n%s\nexpected %+v\nreceived %+v", b, records, parsed)
+	}
+}
+
+func TestQuoteStrings(t *testing.T) {
+	long := strings.Repeat("a", 300)
+
+	if q := quoteStrings([]string{long}); q != `"`+long[:255]+`" "`+long[255:]+`"` {
+		t.Errorf("expected long strings to be split, got %s", q)
+	}
+	if q := quoteStrings([]string{`say "hi"`}); q != `"say \"hi\""` {
+		t.Errorf("expected quotes to be escaped, got %s", q)
+	}
+}
+
+func TestWriteSerial(t *testing.T) {
+	z := api.Zone{
+		Name: "example.org",
+		Records: []*api.Record{
+			nil,
+			{Host: "@", Type: api.RecordTypeSOA, TTL: 300, Data: "ns1.example.org.", RespPerson: "hostmaster.example.org.", Refresh: 10800, Retry: 3600, Expire: 1209600},
+		},
+	}
+
+	var buf strings.Builder
+	if err := WriteSerial(&buf, z, 2019010101); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "2019010101\t; serial") {
+		t.Errorf("expected the given serial, got\n%s", buf.String())
+	}
+
+	if s := dateSerial(time.Date(2019, 1, 2, 13, 0, 0, 0, time.UTC)); s != 2019010213 {
+		t.Errorf("unexpected date serial %d", s)
+	}
+}
+
+func TestParseSOAMinimum(t *testing.T) {
+	for input, ttl := range map[string]int{
+		"@ 3600 IN SOA ns1 hostmaster 1 10800 3600 1209600 300\n": 300,
+		"@ 300 IN SOA ns1 hostmaster 1 10800 3600 1209600 3600\n": 300,
+		"@ IN SOA ns1 hostmaster 1 10800 3600 1209600 900\n":      900,
+	} {
+		records, err := Parse(strings.NewReader(input), "example.org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if records[0].TTL != ttl {
+			t.Errorf("%q: expected TTL %d, got %d", input, ttl, records[0].TTL)
+		}
+	}
+}